@@ -14,6 +14,11 @@ import (
 
 // @host      localhost:8080
 // @schemes   http
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 API key as "Bearer <key>"
 func main() {
 	if err := api.Run(); err != nil {
 		slog.Error("Application failed", "error", err)
@@ -4,14 +4,19 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/LevanPro/insider/internal/config"
+	"github.com/LevanPro/insider/internal/domain"
 	"github.com/LevanPro/insider/internal/infra/database"
+	"github.com/LevanPro/insider/internal/infra/keyring"
+	"github.com/LevanPro/insider/internal/infra/leader"
 	"github.com/LevanPro/insider/internal/infra/logger"
+	"github.com/LevanPro/insider/internal/infra/oidc"
 	"github.com/LevanPro/insider/internal/infra/scheduler"
 	"github.com/LevanPro/insider/internal/infra/sender"
 	"github.com/LevanPro/insider/internal/repository"
@@ -21,10 +26,30 @@ import (
 )
 
 type App struct {
-	log       *zap.SugaredLogger
-	db        *sqlx.DB
-	service   *service.MessageService
-	scheduler *scheduler.Scheduler
+	log              *zap.SugaredLogger
+	db               *sqlx.DB
+	service          *service.MessageService
+	schedulerService *service.SchedulerService
+	campaigns        *service.CampaignService
+	templates        *service.TemplateService
+	enqueue          *service.EnqueueService
+	suppressions     *service.SuppressionService
+	inbound          *service.InboundService
+	apiKeys          *service.APIKeyService
+	oidc             *service.OIDCService
+	audits           *service.AuditService
+	tenants          *service.TenantService
+	usage            *service.UsageService
+	reports          *service.ReportService
+	imports          *service.ImportService
+	retention        *service.RetentionService
+	privacy          *service.PrivacyService
+	authEnabled      bool
+	maskNumbers      bool
+	maskContent      bool
+	trustedProxies   []netip.Prefix
+	scheduler        *scheduler.Scheduler
+	batchSize        int
 }
 
 func Run() error {
@@ -43,6 +68,19 @@ func Run() error {
 		return fmt.Errorf("error loading config %w", err)
 	}
 
+	trustedProxies, err := parseTrustedProxies(cfg.Web.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("invalid web config: %w", err)
+	}
+
+	if err := service.ValidateAdminKey(cfg.Auth.AdminKey, cfg.Application.Environment == "dev"); err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	if cfg.Privacy.MaskNumbers {
+		log = logger.Redact(log, "to", "from", "number")
+	}
+
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 
@@ -77,18 +115,228 @@ func Run() error {
 	}
 
 	// ===================================================================
-	postgresMessageRepo := repository.NewPostgresMessageRepository(db)
-	senderClient := sender.NewClient(cfg.Application.WebhookURL, cfg.Application.WebhookAuthKey)
+	// Message content is sealed with keys from the keyfile when encryption
+	// is enabled.
+	var contentCipher repository.ContentCipher
+	if cfg.Encryption.Enabled {
+		if cfg.Encryption.BatchSize <= 0 || cfg.Encryption.MaxBatches <= 0 {
+			return fmt.Errorf("invalid encryption config: batch size and max batches must be positive")
+		}
+		ring, err := keyring.Load(cfg.Encryption.Keyfile)
+		if err != nil {
+			return fmt.Errorf("invalid encryption config: %w", err)
+		}
+		contentCipher = ring
+		log.Infow("startup", "status", "message encryption enabled", "activeKey", ring.ActiveKeyID())
+	}
+
+	postgresMessageRepo := repository.NewPostgresMessageRepository(db, contentCipher)
+	postgresSuppressionRepo := repository.NewPostgresSuppressionRepository(db)
+
+	// Every webhook client shares one transport and so one connection pool.
+	// Requests carry an HMAC signature when signing is enabled.
+	transport, err := sender.NewTransport(sender.TransportConfig{
+		CertFile:            cfg.SenderTransport.CertFile,
+		KeyFile:             cfg.SenderTransport.KeyFile,
+		CAFile:              cfg.SenderTransport.CAFile,
+		MinTLSVersion:       cfg.SenderTransport.MinTLSVersion,
+		ProxyURL:            cfg.SenderTransport.ProxyURL,
+		MaxIdleConns:        cfg.SenderTransport.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.SenderTransport.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     cfg.SenderTransport.MaxConnsPerHost,
+		IdleConnTimeout:     cfg.SenderTransport.IdleConnTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid sender transport config: %w", err)
+	}
+	defer transport.CloseIdleConnections()
+
+	senderOpts := []sender.Option{sender.WithTransport(transport)}
+	if cfg.Signing.Enabled {
+		if len(cfg.Signing.Secrets) == 0 {
+			return fmt.Errorf("invalid signing config: at least one secret is required")
+		}
+		senderOpts = append(senderOpts, sender.WithSigning(sender.Signing{
+			Secrets:         cfg.Signing.Secrets,
+			SignatureHeader: cfg.Signing.SignatureHeader,
+			TimestampHeader: cfg.Signing.TimestampHeader,
+		}))
+		log.Infow("startup", "status", "webhook signing enabled", "secrets", len(cfg.Signing.Secrets))
+	}
+
+	senderClient := sender.NewClient(cfg.Application.WebhookURL, cfg.Application.WebhookAuthKey, senderOpts...)
+
+	// Tenants send through their own webhook; messages without a tenant
+	// use the application webhook.
+	postgresTenantRepo := repository.NewPostgresTenantRepository(db)
+	tenantSenders := service.NewTenantSenders(postgresTenantRepo, senderClient, cfg.Application.Provider, func(url, authKey string) service.Sender {
+		return sender.NewClient(url, authKey, senderOpts...)
+	})
+
+	frequencyCap := service.FrequencyCap{
+		MaxMessages: cfg.FrequencyCap.MaxMessages,
+		Window:      cfg.FrequencyCap.Window,
+		Policy:      service.FrequencyCapPolicy(cfg.FrequencyCap.Policy),
+	}
+	if err := frequencyCap.Validate(); err != nil {
+		return fmt.Errorf("invalid frequency cap config: %w", err)
+	}
+
+	prices := make([]service.Price, 0, len(cfg.Pricing.Prices))
+	for _, p := range cfg.Pricing.Prices {
+		prices = append(prices, service.Price{Provider: p.Provider, Prefix: p.Prefix, PerSegment: p.PerSegment})
+	}
+	priceTable, err := service.NewPriceTable(prices)
+	if err != nil {
+		return fmt.Errorf("invalid pricing config: %w", err)
+	}
+
+	messageService := service.NewMessageService(postgresMessageRepo, postgresSuppressionRepo, tenantSenders, cfg.Application.BatchSize, cfg.Application.NumberOfWorkers, frequencyCap, priceTable, log)
+	messageScheduler := scheduler.NewScheduler(messageService.ProcessNextUnsent, cfg.Application.SchedulerInterval, cfg.Application.SchedulerStartImmediate)
+
+	// ========== Leader election ========================================
+	// Every replica reconciles periodically, but only the replica holding
+	// the advisory lock runs the message scheduler.
+
+	postgresSchedulerStateRepo := repository.NewPostgresSchedulerStateRepository(db)
+	elector := leader.NewElector(db, cfg.LeaderElection.LockKey)
+	schedulerService := service.NewSchedulerService(postgresSchedulerStateRepo, messageScheduler, elector, log)
+
+	schedulerService.LogStartupState(context.Background())
+
+	reconciler := scheduler.NewScheduler(schedulerService.Reconcile, cfg.LeaderElection.Interval, true)
+	reconciler.Start()
+	defer func() {
+		log.Infow("shutdown", "status", "stopping scheduler and resigning leadership")
+		reconciler.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Web.ShutdownTimeout)
+		defer cancel()
+
+		if err := schedulerService.Shutdown(ctx); err != nil {
+			log.Errorw("shutdown", "ERROR", err)
+		}
+	}()
+
+	// ========== Retention ===============================================
+	// Runs on every replica, but only the leader purges.
+
+	retentionPolicy := service.RetentionPolicy{
+		Mode:       cfg.Retention.Mode,
+		After:      time.Duration(cfg.Retention.Days) * 24 * time.Hour,
+		BatchSize:  cfg.Retention.BatchSize,
+		MaxBatches: cfg.Retention.MaxBatches,
+	}
+	for _, status := range cfg.Retention.Statuses {
+		retentionPolicy.Statuses = append(retentionPolicy.Statuses, domain.MessageStatus(status))
+	}
+
+	postgresRetentionRepo := repository.NewPostgresRetentionRepository(db)
+	retentionService := service.NewRetentionService(postgresRetentionRepo, elector, retentionPolicy, log)
+
+	if cfg.Retention.Enabled {
+		if err := retentionPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid retention config: %w", err)
+		}
+
+		retentionScheduler := scheduler.NewScheduler(retentionService.Run, cfg.Retention.Interval, true)
+		retentionScheduler.Start()
+		defer retentionScheduler.Stop()
+
+		log.Infow("startup", "status", "retention enabled", "mode", retentionPolicy.Mode, "days", cfg.Retention.Days)
+	}
+
+	// ========== Encryption ==============================================
+	// Encrypts messages stored before encryption was enabled or under a
+	// retired key. Runs on every replica, but only the leader encrypts.
+
+	if cfg.Encryption.Enabled {
+		encryptionService := service.NewEncryptionService(postgresMessageRepo, elector, cfg.Encryption.BatchSize, cfg.Encryption.MaxBatches, log)
+
+		encryptionScheduler := scheduler.NewScheduler(encryptionService.Run, cfg.Encryption.Interval, true)
+		encryptionScheduler.Start()
+		defer encryptionScheduler.Stop()
+	}
+
+	// ===================================================================
+
+	postgresCampaignRepo := repository.NewPostgresCampaignRepository(db)
+	campaignService := service.NewCampaignService(postgresCampaignRepo, log)
+
+	postgresTemplateRepo := repository.NewPostgresTemplateRepository(db)
+	templateService := service.NewTemplateService(postgresTemplateRepo, log)
+	postgresUsageRepo := repository.NewPostgresUsageRepository(db)
+	usageService := service.NewUsageService(postgresUsageRepo, log)
+	enqueueService := service.NewEnqueueService(postgresMessageRepo, postgresCampaignRepo, postgresSuppressionRepo, templateService, usageService, cfg.Dedup.Window, log)
+	suppressionService := service.NewSuppressionService(postgresSuppressionRepo, log)
+
+	// Imports run in the background; on shutdown they stop and record how
+	// far they got.
+	postgresImportRepo := repository.NewPostgresImportRepository(db)
+	importService := service.NewImportService(postgresImportRepo, enqueueService, log)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Web.ShutdownTimeout)
+		defer cancel()
 
-	messageService := service.NewMessageService(postgresMessageRepo, senderClient, cfg.Application.BatchSize, cfg.Application.NumberOfWorkers, log)
-	scheduler := scheduler.NewScheduler(messageService.ProcessNextUnsent, cfg.Application.SchedulerInterval, cfg.Application.SchedulerStartImmediate)
-	scheduler.Start()
+		if err := importService.Shutdown(ctx); err != nil {
+			log.Errorw("shutdown", "ERROR", err)
+		}
+	}()
+
+	postgresInboundMessageRepo := repository.NewPostgresInboundMessageRepository(db)
+	inboundService := service.NewInboundService(postgresInboundMessageRepo, postgresSuppressionRepo, log)
+
+	postgresAPIKeyRepo := repository.NewPostgresAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(postgresAPIKeyRepo, cfg.Auth.AdminKey, log)
+	if !cfg.Auth.Enabled {
+		log.Warnw("startup", "status", "authentication is disabled, every caller has all scopes")
+	}
+
+	tenantService := service.NewTenantService(postgresTenantRepo, tenantSenders, log)
+
+	postgresAuditRepo := repository.NewPostgresAuditRepository(db)
+	auditService := service.NewAuditService(postgresAuditRepo, log)
+
+	postgresReportRepo := repository.NewPostgresReportRepository(db)
+	reportService := service.NewReportService(postgresReportRepo, cfg.Pricing.Currency, log)
+
+	postgresPrivacyRepo := repository.NewPostgresPrivacyRepository(db)
+	privacyService := service.NewPrivacyService(postgresPrivacyRepo, log)
+
+	var oidcService *service.OIDCService
+	if cfg.OIDC.Enabled {
+		oidcService, err = newOIDCService(cfg.OIDC, log)
+		if err != nil {
+			return fmt.Errorf("invalid oidc config: %w", err)
+		}
+		log.Infow("startup", "status", "oidc authentication enabled", "issuer", cfg.OIDC.Issuer)
+	}
 
 	app := &App{
-		db:        db,
-		log:       log,
-		scheduler: scheduler,
-		service:   messageService,
+		db:               db,
+		log:              log,
+		scheduler:        messageScheduler,
+		schedulerService: schedulerService,
+		campaigns:        campaignService,
+		templates:        templateService,
+		enqueue:          enqueueService,
+		suppressions:     suppressionService,
+		inbound:          inboundService,
+		apiKeys:          apiKeyService,
+		oidc:             oidcService,
+		audits:           auditService,
+		tenants:          tenantService,
+		usage:            usageService,
+		reports:          reportService,
+		imports:          importService,
+		retention:        retentionService,
+		privacy:          privacyService,
+		authEnabled:      cfg.Auth.Enabled,
+		maskNumbers:      cfg.Privacy.MaskNumbers,
+		maskContent:      cfg.Privacy.MaskContent,
+		trustedProxies:   trustedProxies,
+		service:          messageService,
+		batchSize:        cfg.Application.BatchSize,
 	}
 
 	api := http.Server{
@@ -125,3 +373,31 @@ func Run() error {
 
 	return nil
 }
+
+func newOIDCService(cfg config.OIDC, log *zap.SugaredLogger) (*service.OIDCService, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, fmt.Errorf("issuer and audience are required")
+	}
+
+	if err := service.ValidateRoleScopes(cfg.RoleScopes); err != nil {
+		return nil, err
+	}
+
+	var keys oidc.KeySource
+	switch {
+	case cfg.JWKSFile != "":
+		source, err := oidc.NewFileKeySource(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = source
+	case cfg.JWKSURL != "":
+		keys = oidc.NewURLKeySource(cfg.JWKSURL, cfg.RefreshInterval)
+	default:
+		return nil, fmt.Errorf("either jwks_file or jwks_url is required")
+	}
+
+	verifier := oidc.NewVerifier(keys, cfg.Issuer, cfg.Audience, cfg.Leeway)
+
+	return service.NewOIDCService(verifier, cfg.RolesClaim, cfg.NameClaim, cfg.RoleScopes, log), nil
+}
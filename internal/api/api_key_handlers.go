@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type createAPIKeyRequest struct {
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+	TenantID *int64   `json:"tenant_id"`
+	domain.Quota
+}
+
+type createAPIKeyResponse struct {
+	Key    string        `json:"key"`
+	APIKey domain.APIKey `json:"api_key"`
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Returns the plaintext key once; only its hash is stored. The key cannot hold scopes the caller lacks or a looser quota than the caller's
+// @Tags         keys
+// @Accept       json
+// @Param        request  body  createAPIKeyRequest  true  "Name and scopes"
+// @Success      201  {object} createAPIKeyResponse
+// @Failure      400  {object} map[string]string
+// @Failure      403  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/keys [post]
+func (app *App) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateAPIKey", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	granter, _ := principalFrom(r.Context())
+
+	key, plaintext, err := app.apiKeys.Create(r.Context(), granter, req.Name, req.Scopes, tenantScope(r, req.TenantID), req.Quota, actor(r))
+
+	switch {
+	case errors.Is(err, service.ErrInvalidAPIKey), errors.Is(err, service.ErrUnknownTenant):
+		app.errorResponse(w, "CreateAPIKey", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrGrantExceeded):
+		app.errorResponse(w, "CreateAPIKey", http.StatusForbidden, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateAPIKey", "ERROR", err)
+		app.errorResponse(w, "CreateAPIKey", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditAPIKeyCreate, target("api_key", key.ID), req)
+
+	if err := response(w, http.StatusCreated, createAPIKeyResponse{
+		Key:    plaintext,
+		APIKey: key,
+	}); err != nil {
+		app.log.Errorw("CreateAPIKey", "ERROR", err)
+	}
+}
+
+// ListAPIKeys godoc
+// @Summary      List API keys
+// @Tags         keys
+// @Param        limit   query   int   false  "Limit (default 50)"
+// @Param        offset  query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.APIKey
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/keys [get]
+func (app *App) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	keys, err := app.apiKeys.List(r.Context(), tenantScope(r, nil), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListAPIKeys", "ERROR", err)
+		app.errorResponse(w, "ListAPIKeys", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": keys,
+	}); err != nil {
+		app.log.Errorw("ListAPIKeys", "ERROR", err)
+	}
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Tags         keys
+// @Param        id   path  int  true  "API key ID"
+// @Success      200  {object} domain.APIKey
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/keys/{id} [delete]
+func (app *App) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "RevokeAPIKey", http.StatusBadRequest, "invalid api key id")
+		return
+	}
+
+	key, err := app.apiKeys.Revoke(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		app.errorResponse(w, "RevokeAPIKey", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("RevokeAPIKey", "ERROR", err)
+		app.errorResponse(w, "RevokeAPIKey", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditAPIKeyRevoke, target("api_key", key.ID), nil)
+
+	if err := response(w, http.StatusOK, key); err != nil {
+		app.log.Errorw("RevokeAPIKey", "ERROR", err)
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+// ListAudit godoc
+// @Summary      List audit log entries
+// @Description  Returns state-changing actions, newest first
+// @Tags         audit
+// @Param        actor          query   string  false  "Actor, e.g. api_key:crm"
+// @Param        action         query   string  false  "Action, e.g. scheduler.stop"
+// @Param        target         query   string  false  "Target, e.g. message:42"
+// @Param        created_from   query   string  false  "RFC 3339 lower bound"
+// @Param        created_until  query   string  false  "RFC 3339 upper bound"
+// @Param        limit          query   int     false  "Limit (default 50)"
+// @Param        offset         query   int     false  "Offset (default 0)"
+// @Success      200  {array}  domain.AuditEntry
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/audit [get]
+func (app *App) ListAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter := domain.AuditFilter{
+		Actor:  q.Get("actor"),
+		Action: q.Get("action"),
+		Target: q.Get("target"),
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTimeQuery(r, "created_from"); err != nil {
+		app.errorResponse(w, "ListAudit", http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.CreatedUntil, err = parseTimeQuery(r, "created_until"); err != nil {
+		app.errorResponse(w, "ListAudit", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	entries, err := app.audits.List(r.Context(), filter, limit, offset)
+	if err != nil {
+		app.log.Errorw("ListAudit", "ERROR", err)
+		app.errorResponse(w, "ListAudit", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": entries,
+	}); err != nil {
+		app.log.Errorw("ListAudit", "ERROR", err)
+	}
+}
+
+// audit records a completed action of the caller. Failures are logged
+// rather than returned because the action itself has already happened.
+func (app *App) audit(r *http.Request, action, target string, params any) {
+	err := app.audits.Record(r.Context(), service.AuditRecord{
+		Actor:    actor(r),
+		Action:   action,
+		Target:   target,
+		Params:   params,
+		SourceIP: sourceIP(r),
+	})
+	if err != nil {
+		app.log.Errorw("audit", "ERROR", err, "action", action, "target", target)
+	}
+}
+
+func target(kind string, id any) string {
+	return fmt.Sprintf("%s:%v", kind, id)
+}
+
+// sourceIP returns the client address. realIP has already replaced
+// RemoteAddr with the forwarded address when behind a trusted proxy.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type createCampaignRequest struct {
+	Name               string `json:"name"`
+	DedupWindowSeconds *int   `json:"dedup_window_seconds"`
+	TenantID           *int64 `json:"tenant_id"`
+}
+
+// CreateCampaign godoc
+// @Summary      Create a campaign
+// @Tags         campaigns
+// @Accept       json
+// @Param        request  body  createCampaignRequest  true  "Campaign"
+// @Success      201  {object} domain.Campaign
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/campaigns [post]
+func (app *App) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req createCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateCampaign", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	c, err := app.campaigns.Create(r.Context(), domain.Campaign{
+		Name:               req.Name,
+		DedupWindowSeconds: req.DedupWindowSeconds,
+		TenantID:           tenantScope(r, req.TenantID),
+	})
+
+	switch {
+	case errors.Is(err, service.ErrCampaignNameMissing), errors.Is(err, service.ErrInvalidDedupWindow), errors.Is(err, service.ErrUnknownTenant):
+		app.errorResponse(w, "CreateCampaign", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateCampaign", "ERROR", err)
+		app.errorResponse(w, "CreateCampaign", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditCampaignCreate, target("campaign", c.ID), req)
+
+	if err := response(w, http.StatusCreated, c); err != nil {
+		app.log.Errorw("CreateCampaign", "ERROR", err)
+	}
+}
+
+// ListCampaigns godoc
+// @Summary      List campaigns
+// @Tags         campaigns
+// @Param        tenant_id  query   int   false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        limit      query   int   false  "Limit (default 50)"
+// @Param        offset     query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.Campaign
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/campaigns [get]
+func (app *App) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "ListCampaigns", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	campaigns, err := app.campaigns.List(r.Context(), tenantScope(r, tenantID), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListCampaigns", "ERROR", err)
+		app.errorResponse(w, "ListCampaigns", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": campaigns,
+	}); err != nil {
+		app.log.Errorw("ListCampaigns", "ERROR", err)
+	}
+}
+
+// GetCampaign godoc
+// @Summary      Get a campaign
+// @Description  Returns the campaign with its message counts per status
+// @Tags         campaigns
+// @Param        id   path  int  true  "Campaign ID"
+// @Success      200  {object} service.CampaignDetails
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/campaigns/{id} [get]
+func (app *App) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "GetCampaign", http.StatusBadRequest, "invalid campaign id")
+		return
+	}
+
+	details, err := app.campaigns.Get(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		app.errorResponse(w, "GetCampaign", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetCampaign", "ERROR", err)
+		app.errorResponse(w, "GetCampaign", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, details); err != nil {
+		app.log.Errorw("GetCampaign", "ERROR", err)
+	}
+}
+
+// PauseCampaign godoc
+// @Summary      Pause a campaign
+// @Description  Pending messages of a paused campaign are not sent until it is resumed
+// @Tags         campaigns
+// @Param        id   path  int  true  "Campaign ID"
+// @Success      200  {object} domain.Campaign
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/campaigns/{id}/pause [post]
+func (app *App) PauseCampaign(w http.ResponseWriter, r *http.Request) {
+	app.changeCampaignStatus(w, r, "PauseCampaign", domain.AuditCampaignPause, app.campaigns.Pause)
+}
+
+// ResumeCampaign godoc
+// @Summary      Resume a campaign
+// @Tags         campaigns
+// @Param        id   path  int  true  "Campaign ID"
+// @Success      200  {object} domain.Campaign
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/campaigns/{id}/resume [post]
+func (app *App) ResumeCampaign(w http.ResponseWriter, r *http.Request) {
+	app.changeCampaignStatus(w, r, "ResumeCampaign", domain.AuditCampaignResume, app.campaigns.Resume)
+}
+
+func (app *App) changeCampaignStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	handler string,
+	action string,
+	change func(context.Context, int64, *int64) (domain.Campaign, error),
+) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, handler, http.StatusBadRequest, "invalid campaign id")
+		return
+	}
+
+	c, err := change(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrCampaignNotFound):
+		app.errorResponse(w, handler, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw(handler, "ERROR", err)
+		app.errorResponse(w, handler, http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, action, target("campaign", c.ID), nil)
+
+	if err := response(w, http.StatusOK, c); err != nil {
+		app.log.Errorw(handler, "ERROR", err)
+	}
+}
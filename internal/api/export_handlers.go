@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+// exportFlushEvery is how many rows are written between flushes, so the
+// client receives the export while it is being read.
+const exportFlushEvery = 500
+
+var exportColumns = []string{
+	"id", "to", "content", "status", "campaign_id", "template_id", "tenant_id", "attempts", "last_error",
+	"external_id", "provider", "segments", "unit_price", "created_at", "sent_at", "updated_at",
+}
+
+// exportedMessage is a message as written to an export.
+type exportedMessage struct {
+	ID         int64                `json:"id"`
+	To         string               `json:"to"`
+	Content    string               `json:"content"`
+	Status     domain.MessageStatus `json:"status"`
+	CampaignID *int64               `json:"campaign_id"`
+	TemplateID *int64               `json:"template_id"`
+	TenantID   *int64               `json:"tenant_id"`
+	Attempts   int                  `json:"attempts"`
+	LastError  *string              `json:"last_error"`
+	ExternalID *string              `json:"external_id"`
+	Provider   *string              `json:"provider"`
+	Segments   *int                 `json:"segments"`
+	UnitPrice  *float64             `json:"unit_price"`
+	CreatedAt  time.Time            `json:"created_at"`
+	SentAt     *time.Time           `json:"sent_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+func newExportedMessage(m domain.Message) exportedMessage {
+	return exportedMessage{
+		ID:         m.ID,
+		To:         m.To,
+		Content:    m.Content,
+		Status:     m.Status,
+		CampaignID: m.CampaignID,
+		TemplateID: m.TemplateID,
+		TenantID:   m.TenantID,
+		Attempts:   m.Attempts,
+		LastError:  m.LastError,
+		ExternalID: m.ExternalID,
+		Provider:   m.Provider,
+		Segments:   m.Segments,
+		UnitPrice:  m.UnitPrice,
+		CreatedAt:  m.CreatedAt,
+		SentAt:     m.SentAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// csvRecord renders the message in the order of exportColumns. Missing
+// values are empty.
+func (m exportedMessage) csvRecord() []string {
+	record := []string{
+		strconv.FormatInt(m.ID, 10),
+		m.To,
+		m.Content,
+		string(m.Status),
+		optionalInt(m.CampaignID),
+		optionalInt(m.TemplateID),
+		optionalInt(m.TenantID),
+		strconv.Itoa(m.Attempts),
+		optionalString(m.LastError),
+		optionalString(m.ExternalID),
+		optionalString(m.Provider),
+		"",
+		"",
+		m.CreatedAt.UTC().Format(time.RFC3339),
+		"",
+		m.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if m.Segments != nil {
+		record[11] = strconv.Itoa(*m.Segments)
+	}
+	if m.UnitPrice != nil {
+		record[12] = strconv.FormatFloat(*m.UnitPrice, 'f', -1, 64)
+	}
+	if m.SentAt != nil {
+		record[14] = m.SentAt.UTC().Format(time.RFC3339)
+	}
+	return record
+}
+
+// ExportMessages godoc
+// @Summary      Export messages
+// @Description  Streams every message matching the filters in ID order, as CSV with a header row or as
+// @Description  newline-delimited JSON. Errors after the first row can only be seen as a truncated export.
+// @Tags         messages
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        format         query   string  true   "csv or ndjson"
+// @Param        status         query   string  false  "Status"
+// @Param        to             query   string  false  "Recipient number"
+// @Param        campaign_id    query   int     false  "Campaign ID"
+// @Param        created_from   query   string  false  "Created at or after (RFC3339)"
+// @Param        created_until  query   string  false  "Created before (RFC3339)"
+// @Param        tenant_id      query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        mask_content   query   bool    false  "Replace content with [redacted] (default privacy.mask_content)"
+// @Success      200
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/export [get]
+func (app *App) ExportMessages(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "csv" && format != "ndjson" {
+		app.errorResponse(w, "ExportMessages", http.StatusBadRequest, "format must be csv or ndjson")
+		return
+	}
+
+	filter, err := parseMessageFilter(r)
+	if err != nil {
+		app.errorResponse(w, "ExportMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mask, err := app.parseMaskContent(r)
+	if err != nil {
+		app.errorResponse(w, "ExportMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// An export may take far longer than the server's write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		app.log.Warnw("ExportMessages", "ERROR", err)
+	}
+
+	write, flush := app.exportWriter(w, format)
+
+	rows := 0
+	err = app.service.Export(r.Context(), filter, func(msg domain.Message) error {
+		if mask {
+			msg.Content = redactedContent
+		}
+		if err := write(newExportedMessage(msg)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+
+	if err != nil {
+		app.log.Errorw("ExportMessages", "ERROR", err, "rows", rows)
+		// Once rows are written the status is sent, and the client only
+		// sees a cut off export.
+		if rows == 0 {
+			w.Header().Del("Content-Disposition")
+			app.errorResponse(w, "ExportMessages", http.StatusInternalServerError, "something went wrong")
+		}
+		return
+	}
+
+	app.log.Infow("Messages exported", "format", format, "rows", rows, "by", actor(r))
+}
+
+// exportWriter sets the headers of an export and returns functions that
+// write one message and flush what was written to the client.
+func (app *App) exportWriter(w http.ResponseWriter, format string) (func(exportedMessage) error, func() error) {
+	rc := http.NewResponseController(w)
+	filename := "messages-" + time.Now().UTC().Format("20060102T150405Z")
+
+	if format == "ndjson" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.ndjson"`)
+
+		enc := json.NewEncoder(w)
+		return func(m exportedMessage) error { return enc.Encode(m) }, rc.Flush
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+
+	cw := csv.NewWriter(w)
+	header := false
+	write := func(m exportedMessage) error {
+		if !header {
+			header = true
+			if err := cw.Write(exportColumns); err != nil {
+				return err
+			}
+		}
+		return cw.Write(m.csvRecord())
+	}
+	flush := func() error {
+		if !header {
+			// An empty export still has its header row.
+			header = true
+			if err := cw.Write(exportColumns); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+	return write, flush
+}
+
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -3,115 +3,313 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/LevanPro/insider/internal/domain"
 	"github.com/LevanPro/insider/internal/infra/database"
+	"github.com/LevanPro/insider/internal/infra/scheduler"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
 )
 
+const maxTriggerBatchSize = 1000
+
 // SchedulerStatus godoc
 // @Summary      Get scheduler status
-// @Description  Returns scheduler state
+// @Description  Returns the desired scheduler state shared by all replicas and whether this replica is the leader
 // @Tags         scheduler
-// @Success      200  {object} map[string]string
+// @Success      200  {object} service.SchedulerStatus
 // @Failure      500  {object} map[string]string
+// @Security     BearerAuth
 // @Router       /api/v1/scheduler/status [get]
 func (app *App) SchedulerStatus(w http.ResponseWriter, r *http.Request) {
-	status := app.scheduler.IsRunning()
-	statusCode := http.StatusOK
-
-	data := struct {
-		Status bool `json:"running"`
-	}{
-		Status: status,
+	status, err := app.schedulerService.Status(r.Context())
+	if err != nil {
+		app.log.Errorw("SchedulerStatus", "ERROR", err)
+		app.errorResponse(w, "SchedulerStatus", http.StatusInternalServerError, "something went wrong")
+		return
 	}
 
-	if err := response(w, statusCode, data); err != nil {
+	if err := response(w, http.StatusOK, status); err != nil {
 		app.log.Errorw("SchedulerStatus", "ERROR", err)
 	}
 }
 
+type schedulerChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
 // StartScheduler godoc
 // @Summary      Start automatic message sending
-// @Description  Starts background job that every 2 minutes sends 2 unsent messages
+// @Description  Starts background job that every 2 minutes sends 2 unsent messages. The state is shared by all replicas
 // @Tags         scheduler
 // @Success      200  {object} map[string]string
 // @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Accept       json
+// @Param        request  body  schedulerChangeRequest  false  "Optional reason"
+// @Security     BearerAuth
 // @Router       /api/v1/scheduler/start [post]
 func (app *App) StartScheduler(w http.ResponseWriter, r *http.Request) {
-	err := app.scheduler.Start()
+	var req schedulerChangeRequest
+	if err := decodeOptional(r, &req); err != nil {
+		app.errorResponse(w, "StartScheduler", http.StatusBadRequest, "invalid request body")
+		return
+	}
 
-	message := "scheduler has started"
-	statusCode := http.StatusOK
+	err := app.schedulerService.Start(r.Context(), service.SchedulerChange{
+		Actor:  actor(r),
+		Reason: req.Reason,
+	})
 
-	// Need checking not expose internal errors
-	if err != nil {
-		message = err.Error()
-		statusCode = http.StatusConflict
+	switch {
+	case errors.Is(err, service.ErrSchedulerAlreadyRunning):
+		app.errorResponse(w, "StartScheduler", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("StartScheduler", "ERROR", err)
+		app.errorResponse(w, "StartScheduler", http.StatusInternalServerError, "something went wrong")
+		return
 	}
 
 	data := struct {
 		Message string `json:"message"`
 	}{
-		Message: message,
+		Message: "scheduler has started",
 	}
 
-	if err := response(w, statusCode, data); err != nil {
+	app.audit(r, domain.AuditSchedulerStart, "scheduler", req)
+
+	if err := response(w, http.StatusOK, data); err != nil {
 		app.log.Errorw("StartScheduler", "ERROR", err)
 	}
 }
 
 // StopScheduler godoc
 // @Summary      Stop automatic message sending
-// @Description  Stop background job that sends every 2 minutes sends 2 unsent messages
+// @Description  Stop background job that sends every 2 minutes sends 2 unsent messages. The state is shared by all replicas
 // @Tags         scheduler
 // @Success      200  {object} map[string]string
 // @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Accept       json
+// @Param        request  body  schedulerChangeRequest  false  "Optional reason"
+// @Security     BearerAuth
 // @Router       /api/v1/scheduler/stop [post]
 func (app *App) StopScheduler(w http.ResponseWriter, r *http.Request) {
-	err := app.scheduler.Stop()
+	var req schedulerChangeRequest
+	if err := decodeOptional(r, &req); err != nil {
+		app.errorResponse(w, "StopScheduler", http.StatusBadRequest, "invalid request body")
+		return
+	}
 
-	message := "scheduler has stopped"
-	statusCode := http.StatusOK
+	err := app.schedulerService.Stop(r.Context(), service.SchedulerChange{
+		Actor:  actor(r),
+		Reason: req.Reason,
+	})
 
-	// Need checking not expose internal errors
-	if err != nil {
-		message = err.Error()
-		statusCode = http.StatusConflict
+	switch {
+	case errors.Is(err, service.ErrSchedulerAlreadyStopped):
+		app.errorResponse(w, "StopScheduler", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("StopScheduler", "ERROR", err)
+		app.errorResponse(w, "StopScheduler", http.StatusInternalServerError, "something went wrong")
+		return
 	}
 
 	data := struct {
 		Message string `json:"message"`
 	}{
-		Message: message,
+		Message: "scheduler has stopped",
 	}
 
-	if err := response(w, statusCode, data); err != nil {
+	app.audit(r, domain.AuditSchedulerStop, "scheduler", req)
+
+	if err := response(w, http.StatusOK, data); err != nil {
 		app.log.Errorw("StopScheduler", "ERROR", err)
 	}
 }
 
+// TriggerScheduler godoc
+// @Summary      Run one processing cycle now
+// @Description  Processes the next batch of unsent messages out of band on the leader. Fails with 409 on other replicas and while another run is in progress
+// @Tags         scheduler
+// @Accept       json
+// @Param        request  body  object  false  "Optional batch size override, e.g. {\"batch_size\": 10}"
+// @Success      200  {object} service.ProcessResult
+// @Failure      400  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/scheduler/trigger [post]
+func (app *App) TriggerScheduler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		BatchSize int `json:"batch_size"`
+	}
+
+	if err := decodeOptional(r, &req); err != nil {
+		app.errorResponse(w, "TriggerScheduler", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	batchSize := app.batchSize
+	if req.BatchSize != 0 {
+		if req.BatchSize < 0 || req.BatchSize > maxTriggerBatchSize {
+			app.errorResponse(w, "TriggerScheduler", http.StatusBadRequest, "batch_size must be between 1 and 1000")
+			return
+		}
+		batchSize = req.BatchSize
+	}
+
+	// Message processing belongs to the leader; a run on any other replica
+	// would compete with the leader's scheduler for the same backlog.
+	if !app.schedulerService.IsLeader() {
+		app.errorResponse(w, "TriggerScheduler", http.StatusConflict, "this replica is not the leader")
+		return
+	}
+
+	var result service.ProcessResult
+	err := app.scheduler.RunOnce(r.Context(), func(ctx context.Context) error {
+		var err error
+		result, err = app.service.ProcessBatch(ctx, batchSize)
+		return err
+	})
+
+	switch {
+	case errors.Is(err, scheduler.ErrBusy):
+		app.errorResponse(w, "TriggerScheduler", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("TriggerScheduler", "ERROR", err)
+		app.errorResponse(w, "TriggerScheduler", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditSchedulerTrigger, "scheduler", map[string]any{
+		"batch_size": batchSize,
+		"result":     result,
+	})
+
+	if err := response(w, http.StatusOK, result); err != nil {
+		app.log.Errorw("TriggerScheduler", "ERROR", err)
+	}
+}
+
+type createMessageRequest struct {
+	To                 string            `json:"to"`
+	Content            string            `json:"content"`
+	TemplateID         *int64            `json:"template_id"`
+	Variables          map[string]string `json:"variables"`
+	CampaignID         *int64            `json:"campaign_id"`
+	DedupWindowSeconds *int              `json:"dedup_window_seconds"`
+	TenantID           *int64            `json:"tenant_id"`
+}
+
+// CreateMessage godoc
+// @Summary      Enqueue a message
+// @Description  Creates a pending message. Content is either given directly or rendered from template_id with variables.
+// @Description  The message is charged to the daily and monthly segment quotas of its tenant and API key; the
+// @Description  X-Quota-* headers report the tightest remaining quota per period.
+// @Tags         messages
+// @Accept       json
+// @Param        request  body  createMessageRequest  true  "Message"
+// @Success      201  {object} domain.Message
+// @Failure      400  {object} map[string]string
+// @Failure      422  {object} map[string]string
+// @Failure      429  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages [post]
+func (app *App) CreateMessage(w http.ResponseWriter, r *http.Request) {
+	var req createMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateMessage", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	msg, usages, err := app.enqueue.Enqueue(r.Context(), service.EnqueueRequest{
+		To:                 req.To,
+		Content:            req.Content,
+		TemplateID:         req.TemplateID,
+		Variables:          req.Variables,
+		CampaignID:         req.CampaignID,
+		DedupWindowSeconds: req.DedupWindowSeconds,
+		TenantID:           tenantScope(r, req.TenantID),
+		APIKeyID:           apiKeyID(r),
+	})
+
+	setQuotaHeaders(w, usages)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidMessage):
+		app.errorResponse(w, "CreateMessage", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrQuotaExceeded):
+		app.errorResponse(w, "CreateMessage", http.StatusTooManyRequests, err.Error())
+		return
+	case errors.Is(err, service.ErrRecipientSuppressed):
+		app.errorResponse(w, "CreateMessage", http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateMessage", "ERROR", err)
+		app.errorResponse(w, "CreateMessage", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditMessageCreate, target("message", msg.ID), map[string]any{
+		"template_id": req.TemplateID,
+		"campaign_id": req.CampaignID,
+	})
+
+	if err := response(w, http.StatusCreated, msg); err != nil {
+		app.log.Errorw("CreateMessage", "ERROR", err)
+	}
+}
+
 // GetSentMessages godoc
 // @Summary      List sent messages
 // @Description  Returns a paginated list of messages with status = sent
 // @Tags         messages
-// @Param        limit   query   int   false  "Limit (default 50)"
-// @Param        offset  query   int   false  "Offset (default 0)"
+// @Param        tenant_id  query   int   false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        limit      query   int   false  "Limit (default 50)"
+// @Param        offset     query   int   false  "Offset (default 0)"
+// @Param        mask_content  query  bool  false  "Replace content with [redacted] (default privacy.mask_content)"
 // @Success      200  {array}  domain.Message
+// @Failure      400  {object} map[string]string
 // @Failure      500  {object} map[string]string
 // @Router       /messages/sent [get]
 func (app *App) GetSentMessages(w http.ResponseWriter, r *http.Request) {
 	limit := parseIntQuery(r, "limit", 50)
 	offset := parseIntQuery(r, "offset", 0)
 
-	msgs, err := app.service.ListSent(r.Context(), limit, offset)
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "GetSentMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mask, err := app.parseMaskContent(r)
+	if err != nil {
+		app.errorResponse(w, "GetSentMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msgs, err := app.service.ListSent(r.Context(), tenantScope(r, tenantID), limit, offset)
 	if err != nil {
 		err = response(w, http.StatusInternalServerError, map[string]interface{}{
 			"error": "something went wrong",
 		})
 		return
 	}
+	if mask {
+		maskMessages(msgs)
+	}
 
 	if err := response(w, http.StatusOK, map[string]any{
 		"data": msgs,
@@ -120,6 +318,205 @@ func (app *App) GetSentMessages(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GetFailedMessages godoc
+// @Summary      List failed messages
+// @Description  Returns a paginated dead-letter queue of failed messages with their failure reason
+// @Tags         messages
+// @Param        limit          query   int     false  "Limit (default 50)"
+// @Param        offset         query   int     false  "Offset (default 0)"
+// @Param        to             query   string  false  "Recipient number"
+// @Param        campaign_id    query   int     false  "Campaign ID"
+// @Param        created_from   query   string  false  "Created at or after (RFC3339)"
+// @Param        created_until  query   string  false  "Created before (RFC3339)"
+// @Param        tenant_id      query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        mask_content   query   bool    false  "Replace content with [redacted] (default privacy.mask_content)"
+// @Success      200  {array}  domain.Message
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/failed [get]
+func (app *App) GetFailedMessages(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	filter, err := parseMessageFilter(r)
+	if err != nil {
+		app.errorResponse(w, "GetFailedMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mask, err := app.parseMaskContent(r)
+	if err != nil {
+		app.errorResponse(w, "GetFailedMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msgs, err := app.service.ListFailed(r.Context(), filter, limit, offset)
+	if err != nil {
+		app.log.Errorw("GetFailedMessages", "ERROR", err)
+		app.errorResponse(w, "GetFailedMessages", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	if mask {
+		maskMessages(msgs)
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": msgs,
+	}); err != nil {
+		app.log.Errorw("GetFailedMessages", "ERROR", err)
+	}
+}
+
+type requeueRequest struct {
+	IDs    []int64               `json:"ids"`
+	Filter *domain.MessageFilter `json:"filter"`
+	Reason string                `json:"reason"`
+}
+
+// RequeueMessages godoc
+// @Summary      Requeue failed messages
+// @Description  Moves the given failed messages, or all failed messages matching a non-empty filter, back to pending and resets their attempts
+// @Tags         messages
+// @Accept       json
+// @Param        request  body  requeueRequest  true  "Message IDs or filter"
+// @Success      200  {object} map[string]interface{}
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/requeue [post]
+func (app *App) RequeueMessages(w http.ResponseWriter, r *http.Request) {
+	var req requeueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "RequeueMessages", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ids, err := app.service.Requeue(r.Context(), service.RequeueRequest{
+		IDs:      req.IDs,
+		Filter:   req.Filter,
+		TenantID: tenantScope(r, nil),
+		Actor:    actor(r),
+		Reason:   req.Reason,
+	})
+
+	switch {
+	case errors.Is(err, service.ErrNothingToRequeue):
+		app.errorResponse(w, "RequeueMessages", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("RequeueMessages", "ERROR", err)
+		app.errorResponse(w, "RequeueMessages", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditMessageRequeue, "messages", map[string]any{
+		"ids":    ids,
+		"filter": req.Filter,
+		"reason": req.Reason,
+	})
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"requeued": len(ids),
+		"ids":      ids,
+	}); err != nil {
+		app.log.Errorw("RequeueMessages", "ERROR", err)
+	}
+}
+
+// CancelMessage godoc
+// @Summary      Cancel a pending message
+// @Description  Moves a pending message to cancelled. Fails with 409 if it is already processing or has been sent
+// @Tags         messages
+// @Param        id   path  int  true  "Message ID"
+// @Success      200  {object} domain.Message
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/{id} [delete]
+func (app *App) CancelMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "CancelMessage", http.StatusBadRequest, "invalid message id")
+		return
+	}
+
+	msg, err := app.service.Cancel(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrMessageNotFound):
+		app.errorResponse(w, "CancelMessage", http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, service.ErrMessageNotCancellable):
+		app.errorResponse(w, "CancelMessage", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CancelMessage", "ERROR", err)
+		app.errorResponse(w, "CancelMessage", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditMessageCancel, target("message", msg.ID), nil)
+
+	if err := response(w, http.StatusOK, msg); err != nil {
+		app.log.Errorw("CancelMessage", "ERROR", err)
+	}
+}
+
+type cancelRequest struct {
+	IDs    []int64              `json:"ids"`
+	Filter domain.MessageFilter `json:"filter"`
+}
+
+// CancelMessages godoc
+// @Summary      Cancel pending messages in bulk
+// @Description  Cancels the given pending messages, or all pending messages matching the filter
+// @Tags         messages
+// @Accept       json
+// @Param        request  body  cancelRequest  true  "Message IDs or filter"
+// @Success      200  {object} map[string]interface{}
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/cancel [post]
+func (app *App) CancelMessages(w http.ResponseWriter, r *http.Request) {
+	var req cancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CancelMessages", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ids, err := app.service.CancelMatching(r.Context(), service.CancelRequest{
+		IDs:      req.IDs,
+		Filter:   req.Filter,
+		TenantID: tenantScope(r, nil),
+	})
+
+	switch {
+	case errors.Is(err, service.ErrNothingToCancel):
+		app.errorResponse(w, "CancelMessages", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CancelMessages", "ERROR", err)
+		app.errorResponse(w, "CancelMessages", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditMessageCancel, "messages", map[string]any{
+		"ids":    ids,
+		"filter": req.Filter,
+	})
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"cancelled": len(ids),
+		"ids":       ids,
+	}); err != nil {
+		app.log.Errorw("CancelMessages", "ERROR", err)
+	}
+}
+
 func (app *App) Liveness(w http.ResponseWriter, r *http.Request) {
 	data := struct {
 		Status string `json:"status,omitempty"`
@@ -173,6 +570,93 @@ func response(w http.ResponseWriter, statusCode int, data interface{}) error {
 	return nil
 }
 
+func (app *App) errorResponse(w http.ResponseWriter, handler string, statusCode int, message string) {
+	if err := response(w, statusCode, map[string]string{"error": message}); err != nil {
+		app.log.Errorw(handler, "ERROR", err)
+	}
+}
+
+// actor identifies the caller of a state-changing request.
+func actor(r *http.Request) string {
+	principal, ok := principalFrom(r.Context())
+	if !ok {
+		return "anonymous"
+	}
+	switch principal.Type {
+	case service.PrincipalAPIKey:
+		return "api_key:" + principal.Name
+	case service.PrincipalOIDC:
+		return "oidc:" + principal.Name
+	}
+	return principal.Name
+}
+
+// decodeOptional decodes a JSON request body, treating an empty body as valid.
+func decodeOptional(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// parseMessageFilter reads the common message filters from the query string.
+func parseMessageFilter(r *http.Request) (domain.MessageFilter, error) {
+	q := r.URL.Query()
+
+	filter := domain.MessageFilter{
+		Status: domain.MessageStatus(q.Get("status")),
+		To:     q.Get("to"),
+	}
+
+	if raw := q.Get("campaign_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("campaign_id must be an integer")
+		}
+		filter.CampaignID = &id
+	}
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.TenantID = tenantScope(r, tenantID)
+
+	if filter.CreatedFrom, err = parseTimeQuery(r, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedUntil, err = parseTimeQuery(r, "created_until"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseTenantQuery reads the optional tenant_id query parameter.
+func parseTenantQuery(r *http.Request) (*int64, error) {
+	raw := r.URL.Query().Get("tenant_id")
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("tenant_id must be an integer")
+	}
+	return &id, nil
+}
+
+func parseTimeQuery(r *http.Request, key string) (*time.Time, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp", key)
+	}
+	return &t, nil
+}
+
 func parseIntQuery(r *http.Request, key string, def int) int {
 	raw := r.URL.Query().Get(key)
 	if raw == "" {
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImportUploadBytes bounds the size of an uploaded CSV file.
+const maxImportUploadBytes = 10 << 20
+
+// ImportMessages godoc
+// @Summary      Import messages from a CSV file
+// @Description  Enqueues a message per row of an uploaded CSV file in the background. The file needs a "to" column
+// @Description  and either a "content" column or a template_id, in which case the other columns are template
+// @Description  variables. Follow the progress with GET /api/v1/imports/{id}.
+// @Tags         imports
+// @Accept       multipart/form-data
+// @Param        file         formData  file    true   "CSV file with a header row"
+// @Param        template_id  formData  int     false  "Template to render"
+// @Param        campaign_id  formData  int     false  "Campaign of the messages"
+// @Param        tenant_id    formData  int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Success      202  {object} domain.Import
+// @Failure      400  {object} map[string]string
+// @Failure      413  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/import [post]
+func (app *App) ImportMessages(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportUploadBytes)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			app.errorResponse(w, "ImportMessages", http.StatusRequestEntityTooLarge, fmt.Sprintf("file must not exceed %d bytes", maxImportUploadBytes))
+			return
+		}
+		app.errorResponse(w, "ImportMessages", http.StatusBadRequest, "a multipart file field is required")
+		return
+	}
+	defer file.Close()
+
+	req := service.ImportRequest{
+		Filename: header.Filename,
+		File:     file,
+		APIKeyID: apiKeyID(r),
+		Actor:    actor(r),
+	}
+
+	for _, field := range []struct {
+		name string
+		dst  **int64
+	}{
+		{"template_id", &req.TemplateID},
+		{"campaign_id", &req.CampaignID},
+		{"tenant_id", &req.TenantID},
+	} {
+		raw := r.FormValue(field.name)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			app.errorResponse(w, "ImportMessages", http.StatusBadRequest, field.name+" must be an integer")
+			return
+		}
+		*field.dst = &id
+	}
+	req.TenantID = tenantScope(r, req.TenantID)
+
+	imp, err := app.imports.Start(r.Context(), req)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidImport):
+		app.errorResponse(w, "ImportMessages", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("ImportMessages", "ERROR", err)
+		app.errorResponse(w, "ImportMessages", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditMessageImport, target("import", imp.ID), map[string]any{
+		"filename":    imp.Filename,
+		"rows":        imp.TotalRows,
+		"template_id": imp.TemplateID,
+		"campaign_id": imp.CampaignID,
+	})
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/imports/%d", imp.ID))
+	if err := response(w, http.StatusAccepted, imp); err != nil {
+		app.log.Errorw("ImportMessages", "ERROR", err)
+	}
+}
+
+// GetImport godoc
+// @Summary      Get an import
+// @Description  Returns the status and progress of a CSV import
+// @Tags         imports
+// @Param        id   path  int  true  "Import ID"
+// @Success      200  {object} domain.Import
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/imports/{id} [get]
+func (app *App) GetImport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "GetImport", http.StatusBadRequest, "invalid import id")
+		return
+	}
+
+	imp, err := app.imports.Get(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrImportNotFound):
+		app.errorResponse(w, "GetImport", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetImport", "ERROR", err)
+		app.errorResponse(w, "GetImport", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, imp); err != nil {
+		app.log.Errorw("GetImport", "ERROR", err)
+	}
+}
+
+// GetImportErrors godoc
+// @Summary      Download the rejected rows of an import
+// @Description  Returns the rejected rows as CSV with the columns of the upload followed by error and line, so they
+// @Description  can be fixed and uploaded again
+// @Tags         imports
+// @Produce      text/csv
+// @Param        id   path  int  true  "Import ID"
+// @Success      200
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/imports/{id}/errors [get]
+func (app *App) GetImportErrors(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "GetImportErrors", http.StatusBadRequest, "invalid import id")
+		return
+	}
+
+	imp, rowErrors, err := app.imports.Errors(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrImportNotFound):
+		app.errorResponse(w, "GetImportErrors", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetImportErrors", "ERROR", err)
+		app.errorResponse(w, "GetImportErrors", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, imp.ID))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(append([]string{}, imp.Columns...), "error", "line")); err != nil {
+		app.log.Errorw("GetImportErrors", "ERROR", err)
+		return
+	}
+
+	for _, e := range rowErrors {
+		// Short rows are padded, so the error lines up with its column.
+		record := append([]string{}, e.Record...)
+		for len(record) < len(imp.Columns) {
+			record = append(record, "")
+		}
+		if err := cw.Write(append(record, e.Reason, strconv.Itoa(e.Line))); err != nil {
+			app.log.Errorw("GetImportErrors", "ERROR", err)
+			return
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		app.log.Errorw("GetImportErrors", "ERROR", err)
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+type inboundRequest struct {
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	Content    string     `json:"content"`
+	MessageID  string     `json:"messageId"`
+	ReceivedAt *time.Time `json:"receivedAt"`
+}
+
+// InboundCallback godoc
+// @Summary      Receive an inbound message
+// @Description  Stores a reply from a recipient and links it to the last message sent to that number. Opt-out keywords such as STOP suppress the sender
+// @Tags         callbacks
+// @Accept       json
+// @Param        request  body  inboundRequest  true  "Inbound message"
+// @Success      200  {object} domain.InboundMessage
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/callbacks/inbound [post]
+func (app *App) InboundCallback(w http.ResponseWriter, r *http.Request) {
+	var req inboundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "InboundCallback", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	msg, err := app.inbound.Receive(r.Context(), service.InboundRequest{
+		From:       req.From,
+		To:         req.To,
+		Content:    req.Content,
+		ExternalID: req.MessageID,
+		ReceivedAt: req.ReceivedAt,
+	})
+
+	switch {
+	case errors.Is(err, service.ErrInvalidInbound), errors.Is(err, service.ErrInvalidNumber):
+		app.errorResponse(w, "InboundCallback", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("InboundCallback", "ERROR", err)
+		app.errorResponse(w, "InboundCallback", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditInboundReceive, target("inbound_message", msg.ID), map[string]any{
+		"external_id": req.MessageID,
+		"opt_out":     msg.OptOut,
+	})
+
+	if err := response(w, http.StatusOK, msg); err != nil {
+		app.log.Errorw("InboundCallback", "ERROR", err)
+	}
+}
+
+// GetInboundMessages godoc
+// @Summary      List inbound messages
+// @Description  Returns a paginated list of replies, newest first
+// @Tags         messages
+// @Param        from       query   string  false  "Sender number"
+// @Param        tenant_id  query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        limit      query   int     false  "Limit (default 50)"
+// @Param        offset     query   int     false  "Offset (default 0)"
+// @Param        mask_content  query  bool  false  "Replace content with [redacted] (default privacy.mask_content)"
+// @Success      200  {array}  domain.InboundMessage
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/messages/inbound [get]
+func (app *App) GetInboundMessages(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "GetInboundMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mask, err := app.parseMaskContent(r)
+	if err != nil {
+		app.errorResponse(w, "GetInboundMessages", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	msgs, err := app.inbound.List(r.Context(), r.URL.Query().Get("from"), tenantScope(r, tenantID), limit, offset)
+	if err != nil {
+		app.log.Errorw("GetInboundMessages", "ERROR", err)
+		app.errorResponse(w, "GetInboundMessages", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+	if mask {
+		for i := range msgs {
+			msgs[i].Content = redactedContent
+		}
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": msgs,
+	}); err != nil {
+		app.log.Errorw("GetInboundMessages", "ERROR", err)
+	}
+}
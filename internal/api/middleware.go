@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/infra/logger"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type ctxKey int
+
+const principalKey ctxKey = iota
+
+// authenticate resolves the bearer token of the request to a principal.
+// With authentication disabled every caller gets all scopes.
+func (app *App) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !app.authEnabled {
+			name := r.Header.Get("X-Requested-By")
+			if name == "" {
+				name = "anonymous"
+			}
+			principal := domain.Principal{Type: "anonymous", Name: name, Scopes: domain.Scopes}
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), principal)))
+			return
+		}
+
+		token := bearerToken(r)
+
+		var (
+			principal domain.Principal
+			err       error
+		)
+		if app.oidc != nil && isJWT(token) {
+			principal, err = app.oidc.Authenticate(r.Context(), token)
+		} else {
+			principal, err = app.apiKeys.Authenticate(r.Context(), token)
+		}
+
+		switch {
+		case errors.Is(err, service.ErrUnauthenticated):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			app.errorResponse(w, "authenticate", http.StatusUnauthorized, err.Error())
+			return
+		case err != nil:
+			app.log.Errorw("authenticate", "ERROR", err)
+			app.errorResponse(w, "authenticate", http.StatusInternalServerError, "something went wrong")
+			return
+		}
+
+		if principal.Type == service.PrincipalOIDC {
+			app.log.Infow("authenticate", "principal", principal.Name, "subject", principal.ID, "method", r.Method, "path", r.URL.Path)
+		}
+
+		next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), principal)))
+	})
+}
+
+// realIP replaces RemoteAddr with the client address forwarded by a trusted
+// proxy. Forwarded headers from any other peer are ignored, since every
+// client can set them.
+func (app *App) realIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr := app.forwardedFor(r); addr != "" {
+			r.RemoteAddr = addr
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedFor returns the client address forwarded to a trusted peer, or
+// "" when the peer is not trusted. X-Forwarded-For is read from the right,
+// skipping trusted proxies, because only the entries they appended can be
+// believed.
+func (app *App) forwardedFor(r *http.Request) string {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil || !app.trusted(peer.Addr()) {
+		return ""
+	}
+
+	if header := r.Header.Get("X-Forwarded-For"); header != "" {
+		hops := strings.Split(header, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return ""
+			}
+			if !app.trusted(addr) {
+				return addr.String()
+			}
+		}
+		return ""
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.String()
+	}
+	return ""
+}
+
+func (app *App) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range app.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses IPs and CIDRs of trusted proxies.
+func parseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q must be an IP or CIDR", proxy)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// requireScope rejects requests whose principal lacks scope.
+func (app *App) requireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := principalFrom(r.Context())
+			if !ok || !principal.HasScope(scope) {
+				app.errorResponse(w, "requireScope", http.StatusForbidden, "missing scope "+scope)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireUnscoped rejects principals bound to a tenant, for data shared by
+// every tenant such as the suppression list.
+func (app *App) requireUnscoped(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := principalFrom(r.Context())
+		if !ok || principal.TenantID != nil {
+			app.errorResponse(w, "requireUnscoped", http.StatusForbidden, "not available to keys bound to a tenant")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// isJWT tells identity provider tokens apart from API keys, which never
+// contain dots.
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// tenantScope returns the tenant a request is restricted to. Principals
+// bound to a tenant always get their own; others may pick one with
+// requested, or nil for every tenant.
+func tenantScope(r *http.Request, requested *int64) *int64 {
+	if principal, ok := principalFrom(r.Context()); ok && principal.TenantID != nil {
+		return principal.TenantID
+	}
+	return requested
+}
+
+// apiKeyID returns the ID of the API key the request was made with.
+func apiKeyID(r *http.Request) *int64 {
+	principal, ok := principalFrom(r.Context())
+	if !ok || principal.Type != service.PrincipalAPIKey {
+		return nil
+	}
+	id, err := strconv.ParseInt(principal.ID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func withPrincipal(ctx context.Context, p domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+func principalFrom(ctx context.Context) (domain.Principal, bool) {
+	p, ok := ctx.Value(principalKey).(domain.Principal)
+	return p, ok
+}
+
+// redactingLogFormatter masks phone numbers in the logged request URI, such
+// as /api/v1/suppressions/{number} or ?to= filters.
+type redactingLogFormatter struct {
+	middleware.LogFormatter
+}
+
+func (f redactingLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
+	masked := *r
+	masked.RequestURI = logger.MaskNumbers(r.RequestURI)
+	return f.LogFormatter.NewLogEntry(&masked)
+}
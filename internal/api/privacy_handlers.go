@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/infra/logger"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+// redactedContent replaces message content when content is masked.
+const redactedContent = "[redacted]"
+
+// parseMaskContent reads the mask_content query parameter, which defaults
+// to the privacy.mask_content setting.
+func (app *App) parseMaskContent(r *http.Request) (bool, error) {
+	raw := r.URL.Query().Get("mask_content")
+	if raw == "" {
+		return app.maskContent, nil
+	}
+	mask, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("mask_content must be a boolean")
+	}
+	return mask, nil
+}
+
+func maskMessages(msgs []domain.Message) {
+	for i := range msgs {
+		msgs[i].Content = redactedContent
+	}
+}
+
+type forgetRequest struct {
+	Number string `json:"number"`
+}
+
+// ForgetNumber godoc
+// @Summary      Forget a phone number
+// @Description  Anonymizes every message, archived message, reply and rejected import row of the number and
+// @Description  cancels its pending messages. A suppression of the number is kept.
+// @Tags         privacy
+// @Accept       json
+// @Param        request  body  forgetRequest  true  "E.164 phone number"
+// @Success      200  {object} domain.ForgetResult
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/privacy/forget [post]
+func (app *App) ForgetNumber(w http.ResponseWriter, r *http.Request) {
+	var req forgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "ForgetNumber", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := app.privacy.Forget(r.Context(), req.Number)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidNumber):
+		app.errorResponse(w, "ForgetNumber", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("ForgetNumber", "ERROR", err)
+		app.errorResponse(w, "ForgetNumber", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	// The audit log must not keep the number it was asked to forget.
+	app.audit(r, domain.AuditNumberForget, target("number", logger.MaskNumber(req.Number)), map[string]any{
+		"result": result,
+	})
+
+	if err := response(w, http.StatusOK, result); err != nil {
+		app.log.Errorw("ForgetNumber", "ERROR", err)
+	}
+}
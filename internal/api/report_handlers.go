@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+// GetCostReport godoc
+// @Summary      Report message costs
+// @Description  Sums the cost of sent messages by day, campaign, tenant and/or provider. Segments sent without a
+// @Description  configured price are reported as unpriced_segments and are not part of cost.
+// @Tags         reports
+// @Produce      json
+// @Produce      text/csv
+// @Param        group_by     query   string  false  "Comma separated day, campaign, tenant, provider (default day)"
+// @Param        from         query   string  false  "RFC 3339 lower bound of sent_at"
+// @Param        until        query   string  false  "RFC 3339 upper bound of sent_at"
+// @Param        tenant_id    query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        campaign_id  query   int     false  "Campaign ID"
+// @Param        format       query   string  false  "json (default) or csv"
+// @Success      200  {array}  domain.CostRecord
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/reports/cost [get]
+func (app *App) GetCostReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var filter domain.CostFilter
+	if raw := q.Get("group_by"); raw != "" {
+		for _, group := range strings.Split(raw, ",") {
+			filter.GroupBy = append(filter.GroupBy, strings.TrimSpace(group))
+		}
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(r, "from"); err != nil {
+		app.errorResponse(w, "GetCostReport", http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Until, err = parseTimeQuery(r, "until"); err != nil {
+		app.errorResponse(w, "GetCostReport", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "GetCostReport", http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.TenantID = tenantScope(r, tenantID)
+
+	if raw := q.Get("campaign_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			app.errorResponse(w, "GetCostReport", http.StatusBadRequest, "campaign_id must be an integer")
+			return
+		}
+		filter.CampaignID = &id
+	}
+
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		app.errorResponse(w, "GetCostReport", http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	records, err := app.reports.Cost(r.Context(), filter)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidReport):
+		app.errorResponse(w, "GetCostReport", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetCostReport", "ERROR", err)
+		app.errorResponse(w, "GetCostReport", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if format == "csv" {
+		if err := writeCostCSV(w, records, app.reports.Currency()); err != nil {
+			app.log.Errorw("GetCostReport", "ERROR", err)
+		}
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"currency": app.reports.Currency(),
+		"data":     records,
+	}); err != nil {
+		app.log.Errorw("GetCostReport", "ERROR", err)
+	}
+}
+
+// writeCostCSV writes a cost report for spreadsheets. Dimensions the report
+// is not grouped by are left empty.
+func writeCostCSV(w http.ResponseWriter, records []domain.CostRecord, currency string) error {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="cost-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"day", "campaign_id", "tenant_id", "provider", "messages", "segments", "unpriced_segments", "cost", "currency",
+	}); err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		row := []string{
+			"", optionalInt(rec.CampaignID), optionalInt(rec.TenantID), "",
+			strconv.Itoa(rec.Messages),
+			strconv.Itoa(rec.Segments),
+			strconv.Itoa(rec.UnpricedSegments),
+			strconv.FormatFloat(rec.Cost, 'f', 6, 64),
+			currency,
+		}
+		if rec.Day != nil {
+			row[0] = rec.Day.UTC().Format(time.DateOnly)
+		}
+		if rec.Provider != nil {
+			row[3] = *rec.Provider
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func optionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+// GetStats godoc
+// @Summary      Report throughput and failure rates
+// @Description  Counts the messages created in the range by status, with send rates, failure rate and p50/p95
+// @Description  seconds from creation to sending, optionally per group, next to the current pending backlog.
+// @Tags         reports
+// @Param        from       query   string  false  "RFC 3339 lower bound of created_at (default until minus 24h)"
+// @Param        until      query   string  false  "RFC 3339 upper bound of created_at (default now)"
+// @Param        group_by   query   string  false  "hour, day, campaign or tenant"
+// @Param        tenant_id  query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Success      200  {object} domain.StatsReport
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/stats [get]
+func (app *App) GetStats(w http.ResponseWriter, r *http.Request) {
+	filter := domain.StatsFilter{
+		GroupBy: r.URL.Query().Get("group_by"),
+	}
+
+	from, err := parseTimeQuery(r, "from")
+	if err != nil {
+		app.errorResponse(w, "GetStats", http.StatusBadRequest, err.Error())
+		return
+	}
+	if from != nil {
+		filter.From = *from
+	}
+
+	until, err := parseTimeQuery(r, "until")
+	if err != nil {
+		app.errorResponse(w, "GetStats", http.StatusBadRequest, err.Error())
+		return
+	}
+	if until != nil {
+		filter.Until = *until
+	}
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "GetStats", http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.TenantID = tenantScope(r, tenantID)
+
+	report, err := app.reports.Stats(r.Context(), filter)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidReport):
+		app.errorResponse(w, "GetStats", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetStats", "ERROR", err)
+		app.errorResponse(w, "GetStats", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, report); err != nil {
+		app.log.Errorw("GetStats", "ERROR", err)
+	}
+}
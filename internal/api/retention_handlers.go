@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+)
+
+// ListRetentionRuns godoc
+// @Summary      List retention runs
+// @Description  Returns what each run of the retention job archived or deleted, newest first
+// @Tags         retention
+// @Param        limit   query   int   false  "Limit (default 50)"
+// @Param        offset  query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.RetentionRun
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/retention/runs [get]
+func (app *App) ListRetentionRuns(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	runs, err := app.retention.ListRuns(r.Context(), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListRetentionRuns", "ERROR", err)
+		app.errorResponse(w, "ListRetentionRuns", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": runs,
+	}); err != nil {
+		app.log.Errorw("ListRetentionRuns", "ERROR", err)
+	}
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"log"
 	"net/http"
+	"os"
 
+	"github.com/LevanPro/insider/internal/domain"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -11,13 +14,99 @@ import (
 func (app *App) setupRoutes() *chi.Mux {
 	router := chi.NewRouter()
 
+	router.Use(app.realIP)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Logger)
+	if app.maskNumbers {
+		router.Use(middleware.RequestLogger(redactingLogFormatter{
+			LogFormatter: &middleware.DefaultLogFormatter{Logger: log.New(os.Stdout, "", log.LstdFlags)},
+		}))
+	} else {
+		router.Use(middleware.Logger)
+	}
 
-	router.Post("/api/v1/scheduler/start", app.StartScheduler)
-	router.Post("/api/v1/scheduler/stop", app.StopScheduler)
-	router.Get("/api/v1/scheduler/status", app.SchedulerStatus)
-	router.Get("/api/v1/messages/sent", app.GetSentMessages)
+	router.Group(func(r chi.Router) {
+		r.Use(app.authenticate)
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeSchedulerAdmin))
+
+			r.Post("/api/v1/scheduler/start", app.StartScheduler)
+			r.Post("/api/v1/scheduler/stop", app.StopScheduler)
+			r.Get("/api/v1/scheduler/status", app.SchedulerStatus)
+			r.Post("/api/v1/scheduler/trigger", app.TriggerScheduler)
+			r.Get("/api/v1/retention/runs", app.ListRetentionRuns)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeMessagesRead))
+
+			r.Get("/api/v1/messages/sent", app.GetSentMessages)
+			r.Get("/api/v1/messages/failed", app.GetFailedMessages)
+			r.Get("/api/v1/messages/export", app.ExportMessages)
+			r.Get("/api/v1/imports/{id}", app.GetImport)
+			r.Get("/api/v1/imports/{id}/errors", app.GetImportErrors)
+			r.Get("/api/v1/messages/inbound", app.GetInboundMessages)
+			r.Get("/api/v1/campaigns", app.ListCampaigns)
+			r.Get("/api/v1/campaigns/{id}", app.GetCampaign)
+			r.Get("/api/v1/templates", app.ListTemplates)
+			r.Get("/api/v1/templates/{id}", app.GetTemplate)
+			// The suppression list is shared by every tenant, so keys
+			// bound to one may only add to it.
+			r.With(app.requireUnscoped).Get("/api/v1/suppressions", app.ListSuppressions)
+			r.With(app.requireUnscoped).Get("/api/v1/suppressions/{number}", app.GetSuppression)
+			r.Get("/api/v1/usage", app.GetUsage)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeMessagesWrite))
+
+			r.Post("/api/v1/messages", app.CreateMessage)
+			r.Post("/api/v1/messages/import", app.ImportMessages)
+			r.Post("/api/v1/messages/requeue", app.RequeueMessages)
+			r.Post("/api/v1/messages/cancel", app.CancelMessages)
+			r.Delete("/api/v1/messages/{id}", app.CancelMessage)
+
+			r.Post("/api/v1/campaigns", app.CreateCampaign)
+			r.Post("/api/v1/campaigns/{id}/pause", app.PauseCampaign)
+			r.Post("/api/v1/campaigns/{id}/resume", app.ResumeCampaign)
+
+			r.Post("/api/v1/templates", app.CreateTemplate)
+			r.Delete("/api/v1/templates/{id}", app.DeleteTemplate)
+
+			r.Post("/api/v1/suppressions", app.CreateSuppression)
+			r.With(app.requireUnscoped).Put("/api/v1/suppressions/{number}", app.UpdateSuppression)
+			r.With(app.requireUnscoped).Delete("/api/v1/suppressions/{number}", app.DeleteSuppression)
+		})
+
+		r.With(app.requireScope(domain.ScopeCallbacksWrite)).Post("/api/v1/callbacks/inbound", app.InboundCallback)
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeKeysAdmin))
+
+			r.Post("/api/v1/keys", app.CreateAPIKey)
+			r.Get("/api/v1/keys", app.ListAPIKeys)
+			r.Delete("/api/v1/keys/{id}", app.RevokeAPIKey)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeTenantsAdmin))
+
+			r.Post("/api/v1/tenants", app.CreateTenant)
+			r.Get("/api/v1/tenants", app.ListTenants)
+			r.Get("/api/v1/tenants/{id}", app.GetTenant)
+			r.Put("/api/v1/tenants/{id}", app.UpdateTenant)
+		})
+
+		r.With(app.requireScope(domain.ScopeAuditRead)).Get("/api/v1/audit", app.ListAudit)
+		r.With(app.requireScope(domain.ScopePrivacyAdmin)).Post("/api/v1/privacy/forget", app.ForgetNumber)
+
+		r.Group(func(r chi.Router) {
+			r.Use(app.requireScope(domain.ScopeReportsRead))
+
+			r.Get("/api/v1/reports/cost", app.GetCostReport)
+			r.Get("/api/v1/stats", app.GetStats)
+		})
+	})
 
 	router.Get("/debug/liveness", app.Liveness)
 	router.Get("/debug/readiness", app.Readiness)
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type suppressionRequest struct {
+	Number string `json:"number"`
+	Reason string `json:"reason"`
+	Source string `json:"source"`
+}
+
+// CreateSuppression godoc
+// @Summary      Suppress a recipient
+// @Description  Messages to a suppressed number are rejected on enqueue and never sent
+// @Tags         suppressions
+// @Accept       json
+// @Param        request  body  suppressionRequest  true  "Suppression"
+// @Success      201  {object} domain.Suppression
+// @Failure      400  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/suppressions [post]
+func (app *App) CreateSuppression(w http.ResponseWriter, r *http.Request) {
+	var req suppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateSuppression", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sup, err := app.suppressions.Create(r.Context(), domain.Suppression{
+		Number: req.Number,
+		Reason: req.Reason,
+		Source: req.Source,
+	})
+
+	switch {
+	case errors.Is(err, service.ErrInvalidNumber):
+		app.errorResponse(w, "CreateSuppression", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrSuppressionExists):
+		app.errorResponse(w, "CreateSuppression", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateSuppression", "ERROR", err)
+		app.errorResponse(w, "CreateSuppression", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditSuppressionCreate, target("suppression", sup.Number), req)
+
+	if err := response(w, http.StatusCreated, sup); err != nil {
+		app.log.Errorw("CreateSuppression", "ERROR", err)
+	}
+}
+
+// ListSuppressions godoc
+// @Summary      List suppressed recipients
+// @Tags         suppressions
+// @Param        limit   query   int   false  "Limit (default 50)"
+// @Param        offset  query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.Suppression
+// @Failure      403  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/suppressions [get]
+func (app *App) ListSuppressions(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	suppressions, err := app.suppressions.List(r.Context(), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListSuppressions", "ERROR", err)
+		app.errorResponse(w, "ListSuppressions", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": suppressions,
+	}); err != nil {
+		app.log.Errorw("ListSuppressions", "ERROR", err)
+	}
+}
+
+// GetSuppression godoc
+// @Summary      Get a suppressed recipient
+// @Tags         suppressions
+// @Param        number  path  string  true  "Phone number"
+// @Success      200  {object} domain.Suppression
+// @Failure      403  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/suppressions/{number} [get]
+func (app *App) GetSuppression(w http.ResponseWriter, r *http.Request) {
+	sup, err := app.suppressions.Get(r.Context(), chi.URLParam(r, "number"))
+
+	switch {
+	case errors.Is(err, service.ErrSuppressionNotFound):
+		app.errorResponse(w, "GetSuppression", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetSuppression", "ERROR", err)
+		app.errorResponse(w, "GetSuppression", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, sup); err != nil {
+		app.log.Errorw("GetSuppression", "ERROR", err)
+	}
+}
+
+// UpdateSuppression godoc
+// @Summary      Update the reason of a suppression
+// @Tags         suppressions
+// @Accept       json
+// @Param        number   path  string              true  "Phone number"
+// @Param        request  body  suppressionRequest  true  "New reason"
+// @Success      200  {object} domain.Suppression
+// @Failure      400  {object} map[string]string
+// @Failure      403  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/suppressions/{number} [put]
+func (app *App) UpdateSuppression(w http.ResponseWriter, r *http.Request) {
+	var req suppressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "UpdateSuppression", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sup, err := app.suppressions.Update(r.Context(), chi.URLParam(r, "number"), req.Reason)
+
+	switch {
+	case errors.Is(err, service.ErrSuppressionNotFound):
+		app.errorResponse(w, "UpdateSuppression", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("UpdateSuppression", "ERROR", err)
+		app.errorResponse(w, "UpdateSuppression", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditSuppressionUpdate, target("suppression", sup.Number), map[string]string{
+		"reason": req.Reason,
+	})
+
+	if err := response(w, http.StatusOK, sup); err != nil {
+		app.log.Errorw("UpdateSuppression", "ERROR", err)
+	}
+}
+
+// DeleteSuppression godoc
+// @Summary      Remove a recipient from the suppression list
+// @Tags         suppressions
+// @Param        number  path  string  true  "Phone number"
+// @Success      204
+// @Failure      403  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/suppressions/{number} [delete]
+func (app *App) DeleteSuppression(w http.ResponseWriter, r *http.Request) {
+	number := chi.URLParam(r, "number")
+
+	err := app.suppressions.Delete(r.Context(), number)
+
+	switch {
+	case errors.Is(err, service.ErrSuppressionNotFound):
+		app.errorResponse(w, "DeleteSuppression", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("DeleteSuppression", "ERROR", err)
+		app.errorResponse(w, "DeleteSuppression", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditSuppressionDelete, target("suppression", number), nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type createTemplateRequest struct {
+	Name     string `json:"name"`
+	Locale   string `json:"locale"`
+	Body     string `json:"body"`
+	TenantID *int64 `json:"tenant_id"`
+}
+
+// CreateTemplate godoc
+// @Summary      Create a message template
+// @Description  Body placeholders use Go text/template syntax, e.g. "Hello {{.name}}"
+// @Tags         templates
+// @Accept       json
+// @Param        request  body  createTemplateRequest  true  "Template"
+// @Success      201  {object} domain.Template
+// @Failure      400  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/templates [post]
+func (app *App) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	var req createTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateTemplate", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	t, err := app.templates.Create(r.Context(), domain.Template{
+		Name:     req.Name,
+		Locale:   req.Locale,
+		Body:     req.Body,
+		TenantID: tenantScope(r, req.TenantID),
+	})
+
+	switch {
+	case errors.Is(err, service.ErrTemplateInvalid), errors.Is(err, service.ErrUnknownTenant):
+		app.errorResponse(w, "CreateTemplate", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrTemplateExists):
+		app.errorResponse(w, "CreateTemplate", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateTemplate", "ERROR", err)
+		app.errorResponse(w, "CreateTemplate", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditTemplateCreate, target("template", t.ID), map[string]any{
+		"name":      t.Name,
+		"locale":    t.Locale,
+		"tenant_id": t.TenantID,
+	})
+
+	if err := response(w, http.StatusCreated, t); err != nil {
+		app.log.Errorw("CreateTemplate", "ERROR", err)
+	}
+}
+
+// ListTemplates godoc
+// @Summary      List message templates
+// @Tags         templates
+// @Param        tenant_id  query   int   false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        limit      query   int   false  "Limit (default 50)"
+// @Param        offset     query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.Template
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/templates [get]
+func (app *App) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "ListTemplates", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	templates, err := app.templates.List(r.Context(), tenantScope(r, tenantID), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListTemplates", "ERROR", err)
+		app.errorResponse(w, "ListTemplates", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": templates,
+	}); err != nil {
+		app.log.Errorw("ListTemplates", "ERROR", err)
+	}
+}
+
+// GetTemplate godoc
+// @Summary      Get a message template
+// @Tags         templates
+// @Param        id   path  int  true  "Template ID"
+// @Success      200  {object} domain.Template
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/templates/{id} [get]
+func (app *App) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "GetTemplate", http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	t, err := app.templates.Get(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		app.errorResponse(w, "GetTemplate", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetTemplate", "ERROR", err)
+		app.errorResponse(w, "GetTemplate", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, t); err != nil {
+		app.log.Errorw("GetTemplate", "ERROR", err)
+	}
+}
+
+// DeleteTemplate godoc
+// @Summary      Delete a message template
+// @Tags         templates
+// @Param        id   path  int  true  "Template ID"
+// @Success      204
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/templates/{id} [delete]
+func (app *App) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "DeleteTemplate", http.StatusBadRequest, "invalid template id")
+		return
+	}
+
+	err = app.templates.Delete(r.Context(), id, tenantScope(r, nil))
+
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		app.errorResponse(w, "DeleteTemplate", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("DeleteTemplate", "ERROR", err)
+		app.errorResponse(w, "DeleteTemplate", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditTemplateDelete, target("template", id), nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+	"github.com/go-chi/chi/v5"
+)
+
+type tenantRequest struct {
+	Name           string `json:"name"`
+	WebhookURL     string `json:"webhook_url"`
+	WebhookAuthKey string `json:"webhook_auth_key"`
+	Provider       string `json:"provider"`
+	domain.Quota
+}
+
+// CreateTenant godoc
+// @Summary      Create a tenant
+// @Description  Creates a business unit with its own provider webhook and auth key
+// @Tags         tenants
+// @Accept       json
+// @Param        request  body  tenantRequest  true  "Tenant"
+// @Success      201  {object} domain.Tenant
+// @Failure      400  {object} map[string]string
+// @Failure      409  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/tenants [post]
+func (app *App) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req tenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "CreateTenant", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	t, err := app.tenants.Create(r.Context(), domain.Tenant{
+		Name:           req.Name,
+		WebhookURL:     req.WebhookURL,
+		WebhookAuthKey: req.WebhookAuthKey,
+		Provider:       req.Provider,
+		Quota:          req.Quota,
+	})
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTenant):
+		app.errorResponse(w, "CreateTenant", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrTenantExists):
+		app.errorResponse(w, "CreateTenant", http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("CreateTenant", "ERROR", err)
+		app.errorResponse(w, "CreateTenant", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditTenantCreate, target("tenant", t.ID), map[string]any{
+		"name":        t.Name,
+		"webhook_url": t.WebhookURL,
+		"provider":    t.Provider,
+		"quota":       t.Quota,
+	})
+
+	if err := response(w, http.StatusCreated, t); err != nil {
+		app.log.Errorw("CreateTenant", "ERROR", err)
+	}
+}
+
+// ListTenants godoc
+// @Summary      List tenants
+// @Tags         tenants
+// @Param        limit   query   int   false  "Limit (default 50)"
+// @Param        offset  query   int   false  "Offset (default 0)"
+// @Success      200  {array}  domain.Tenant
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/tenants [get]
+func (app *App) ListTenants(w http.ResponseWriter, r *http.Request) {
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+
+	tenants, err := app.tenants.List(r.Context(), limit, offset)
+	if err != nil {
+		app.log.Errorw("ListTenants", "ERROR", err)
+		app.errorResponse(w, "ListTenants", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"data": tenants,
+	}); err != nil {
+		app.log.Errorw("ListTenants", "ERROR", err)
+	}
+}
+
+// GetTenant godoc
+// @Summary      Get a tenant
+// @Tags         tenants
+// @Param        id   path  int  true  "Tenant ID"
+// @Success      200  {object} domain.Tenant
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/tenants/{id} [get]
+func (app *App) GetTenant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "GetTenant", http.StatusBadRequest, "invalid tenant id")
+		return
+	}
+
+	t, err := app.tenants.Get(r.Context(), id)
+
+	switch {
+	case errors.Is(err, service.ErrTenantNotFound):
+		app.errorResponse(w, "GetTenant", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetTenant", "ERROR", err)
+		app.errorResponse(w, "GetTenant", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, t); err != nil {
+		app.log.Errorw("GetTenant", "ERROR", err)
+	}
+}
+
+// UpdateTenant godoc
+// @Summary      Update a tenant's webhook and quotas
+// @Description  Replaces the webhook URL, auth key and quotas of the tenant
+// @Tags         tenants
+// @Accept       json
+// @Param        id       path  int            true  "Tenant ID"
+// @Param        request  body  tenantRequest  true  "Webhook settings and quotas; name is ignored"
+// @Success      200  {object} domain.Tenant
+// @Failure      400  {object} map[string]string
+// @Failure      404  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/tenants/{id} [put]
+func (app *App) UpdateTenant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		app.errorResponse(w, "UpdateTenant", http.StatusBadRequest, "invalid tenant id")
+		return
+	}
+
+	var req tenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		app.errorResponse(w, "UpdateTenant", http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	t, err := app.tenants.Update(r.Context(), domain.Tenant{
+		ID:             id,
+		WebhookURL:     req.WebhookURL,
+		WebhookAuthKey: req.WebhookAuthKey,
+		Provider:       req.Provider,
+		Quota:          req.Quota,
+	})
+
+	switch {
+	case errors.Is(err, service.ErrInvalidTenant):
+		app.errorResponse(w, "UpdateTenant", http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, service.ErrTenantNotFound):
+		app.errorResponse(w, "UpdateTenant", http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("UpdateTenant", "ERROR", err)
+		app.errorResponse(w, "UpdateTenant", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	app.audit(r, domain.AuditTenantUpdate, target("tenant", t.ID), map[string]any{
+		"webhook_url": t.WebhookURL,
+		"provider":    t.Provider,
+		"quota":       t.Quota,
+	})
+
+	if err := response(w, http.StatusOK, t); err != nil {
+		app.log.Errorw("UpdateTenant", "ERROR", err)
+	}
+}
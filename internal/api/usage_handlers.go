@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+// GetUsage godoc
+// @Summary      Report usage
+// @Description  Returns messages and segments enqueued per period, tenant and API key
+// @Tags         usage
+// @Param        period      query   string  false  "day (default) or month"
+// @Param        from        query   string  false  "RFC 3339 lower bound"
+// @Param        until       query   string  false  "RFC 3339 upper bound"
+// @Param        tenant_id   query   int     false  "Tenant ID, ignored for keys bound to a tenant"
+// @Param        api_key_id  query   int     false  "API key ID"
+// @Success      200  {array}  domain.UsageRecord
+// @Failure      400  {object} map[string]string
+// @Failure      500  {object} map[string]string
+// @Security     BearerAuth
+// @Router       /api/v1/usage [get]
+func (app *App) GetUsage(w http.ResponseWriter, r *http.Request) {
+	filter := domain.UsageFilter{
+		Period: r.URL.Query().Get("period"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(r, "from"); err != nil {
+		app.errorResponse(w, "GetUsage", http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.Until, err = parseTimeQuery(r, "until"); err != nil {
+		app.errorResponse(w, "GetUsage", http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenantID, err := parseTenantQuery(r)
+	if err != nil {
+		app.errorResponse(w, "GetUsage", http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.TenantID = tenantScope(r, tenantID)
+
+	if raw := r.URL.Query().Get("api_key_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			app.errorResponse(w, "GetUsage", http.StatusBadRequest, "api_key_id must be an integer")
+			return
+		}
+		filter.APIKeyID = &id
+	}
+
+	records, err := app.usage.List(r.Context(), filter)
+
+	switch {
+	case errors.Is(err, service.ErrInvalidUsagePeriod):
+		app.errorResponse(w, "GetUsage", http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		app.log.Errorw("GetUsage", "ERROR", err)
+		app.errorResponse(w, "GetUsage", http.StatusInternalServerError, "something went wrong")
+		return
+	}
+
+	if err := response(w, http.StatusOK, map[string]any{
+		"period": filter.Period,
+		"data":   records,
+	}); err != nil {
+		app.log.Errorw("GetUsage", "ERROR", err)
+	}
+}
+
+// setQuotaHeaders reports the tightest quota of each period, e.g.
+// X-Quota-Daily-Remaining. When a quota is exceeded, Retry-After tells the
+// caller when it resets.
+func setQuotaHeaders(w http.ResponseWriter, usages []domain.QuotaUsage) {
+	tightest := map[string]domain.QuotaUsage{}
+	var retryAt time.Time
+
+	for _, u := range usages {
+		if current, ok := tightest[u.Period]; !ok || u.Remaining() < current.Remaining() {
+			tightest[u.Period] = u
+		}
+		if u.Exceeded && u.ResetAt.After(retryAt) {
+			retryAt = u.ResetAt
+		}
+	}
+
+	for period, prefix := range map[string]string{
+		domain.UsagePeriodDay:   "X-Quota-Daily-",
+		domain.UsagePeriodMonth: "X-Quota-Monthly-",
+	} {
+		u, ok := tightest[period]
+		if !ok {
+			continue
+		}
+		w.Header().Set(prefix+"Limit", strconv.Itoa(u.Limit))
+		w.Header().Set(prefix+"Remaining", strconv.Itoa(u.Remaining()))
+		w.Header().Set(prefix+"Reset", u.ResetAt.Format(time.RFC3339))
+	}
+
+	if !retryAt.IsZero() {
+		seconds := math.Ceil(time.Until(retryAt).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(seconds), 1)))
+	}
+}
@@ -9,13 +9,27 @@ import (
 )
 
 type Config struct {
-	Web         `yaml:"web"`
-	DB          `yaml:"db"`
-	Application `yaml:"application"`
+	Web             `yaml:"web"`
+	DB              `yaml:"db"`
+	Application     `yaml:"application"`
+	LeaderElection  `yaml:"leader_election"`
+	FrequencyCap    `yaml:"frequency_cap"`
+	Dedup           `yaml:"dedup"`
+	Auth            `yaml:"auth"`
+	OIDC            `yaml:"oidc"`
+	Pricing         `yaml:"pricing"`
+	Retention       `yaml:"retention"`
+	Privacy         `yaml:"privacy"`
+	Encryption      `yaml:"encryption"`
+	Signing         `yaml:"signing"`
+	SenderTransport `yaml:"sender_transport"`
 }
 
+// Web configures the API server. Forwarded client addresses are only
+// believed from TrustedProxies, a list of IPs or CIDRs of reverse proxies.
 type Web struct {
 	Address         string        `yaml:"address" env-default:"0.0.0.0:8080"`
+	TrustedProxies  []string      `yaml:"trusted_proxies" env:"WEB_TRUSTED_PROXIES"`
 	ReadTimeout     time.Duration `yaml:"read_timeout" env-default:"5s"`
 	WriteTimeout    time.Duration `yaml:"write_timeout" env-default:"10s"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"120s"`
@@ -32,13 +46,133 @@ type DB struct {
 	DisableTLS   bool   `yaml:"disable_tls" env-default:"true"`
 }
 
+// Application configures message processing. Environment "dev" relaxes
+// checks meant for deployments, such as the strength of the admin key.
 type Application struct {
+	Environment             string        `yaml:"environment" env:"APP_ENV" env-default:"production"`
 	WebhookURL              string        `yaml:"webhook_url" env-required:""`
 	WebhookAuthKey          string        `yaml:"webhook_auth_key" env-required:""`
 	BatchSize               int           `yaml:"batch_size" env-default:"2"`
 	SchedulerInterval       time.Duration `yaml:"interval_seconds" env-default:"120s"`
 	SchedulerStartImmediate bool          `yaml:"scheduler_immediate" env-default:"true"`
 	NumberOfWorkers         int           `yaml:"num_workers" env-default:"2"`
+	Provider                string        `yaml:"provider" env-default:"default"`
+}
+
+type LeaderElection struct {
+	LockKey  int64         `yaml:"lock_key" env-default:"7234001"`
+	Interval time.Duration `yaml:"interval" env-default:"5s"`
+}
+
+// FrequencyCap limits messages per recipient within a rolling window.
+// MaxMessages of 0 disables it. Policy is either "defer" or "fail".
+type FrequencyCap struct {
+	MaxMessages int           `yaml:"max_messages" env-default:"0"`
+	Window      time.Duration `yaml:"window" env-default:"1h"`
+	Policy      string        `yaml:"policy" env-default:"defer"`
+}
+
+// Dedup is the default deduplication window of new messages. Campaigns and
+// callers can override it; 0 disables deduplication.
+type Dedup struct {
+	Window time.Duration `yaml:"window" env-default:"0s"`
+}
+
+// Auth configures API authentication. AdminKey is a bootstrap key with
+// every scope, used to create the first API keys. It is only read from the
+// environment so it never ends up in a config file.
+type Auth struct {
+	Enabled  bool   `yaml:"enabled" env-default:"true"`
+	AdminKey string `yaml:"-" env:"AUTH_ADMIN_KEY"`
+}
+
+// OIDC enables bearer JWTs issued by an identity provider next to API keys.
+// Keys come from JWKSFile when set, otherwise from JWKSURL. RoleScopes maps
+// the roles found in RolesClaim to API scopes.
+type OIDC struct {
+	Enabled         bool                `yaml:"enabled" env-default:"false"`
+	Issuer          string              `yaml:"issuer"`
+	Audience        string              `yaml:"audience"`
+	JWKSURL         string              `yaml:"jwks_url"`
+	JWKSFile        string              `yaml:"jwks_file"`
+	RefreshInterval time.Duration       `yaml:"refresh_interval" env-default:"1h"`
+	Leeway          time.Duration       `yaml:"leeway" env-default:"30s"`
+	RolesClaim      string              `yaml:"roles_claim" env-default:"roles"`
+	NameClaim       string              `yaml:"name_claim" env-default:"email"`
+	RoleScopes      map[string][]string `yaml:"role_scopes"`
+}
+
+// Pricing is the price per segment by provider and recipient prefix. The
+// longest matching prefix wins; an entry without a provider applies to every
+// provider without an entry of its own.
+type Pricing struct {
+	Currency string  `yaml:"currency" env-default:"EUR"`
+	Prices   []Price `yaml:"prices"`
+}
+
+type Price struct {
+	Provider   string  `yaml:"provider"`
+	Prefix     string  `yaml:"prefix"`
+	PerSegment float64 `yaml:"per_segment"`
+}
+
+// Retention purges finished messages once they are older than Days. Mode
+// "archive" moves them to messages_archive, "delete" drops them. Each run
+// purges at most MaxBatches batches of BatchSize messages.
+type Retention struct {
+	Enabled    bool          `yaml:"enabled" env-default:"false"`
+	Mode       string        `yaml:"mode" env-default:"archive"`
+	Days       int           `yaml:"days" env-default:"90"`
+	Statuses   []string      `yaml:"statuses" env-default:"sent,delivered,failed"`
+	Interval   time.Duration `yaml:"interval" env-default:"1h"`
+	BatchSize  int           `yaml:"batch_size" env-default:"1000"`
+	MaxBatches int           `yaml:"max_batches" env-default:"100"`
+}
+
+// Privacy controls redaction of personal data. MaskNumbers masks phone
+// numbers in logs; MaskContent masks message content in API responses unless
+// a request asks for it with mask_content=false.
+type Privacy struct {
+	MaskNumbers bool `yaml:"mask_numbers" env-default:"true"`
+	MaskContent bool `yaml:"mask_content" env-default:"false"`
+}
+
+// Encryption seals message content at rest with keys from Keyfile. Every
+// Interval the leader encrypts up to MaxBatches batches of BatchSize stored
+// messages that are in plaintext or sealed under a retired key. Once enabled
+// it must stay enabled, with every key still in use kept in the keyfile.
+type Encryption struct {
+	Enabled    bool          `yaml:"enabled" env-default:"false"`
+	Keyfile    string        `yaml:"keyfile" env:"ENCRYPTION_KEYFILE"`
+	Interval   time.Duration `yaml:"interval" env-default:"1m"`
+	BatchSize  int           `yaml:"batch_size" env-default:"500"`
+	MaxBatches int           `yaml:"max_batches" env-default:"20"`
+}
+
+// Signing signs webhook requests with HMAC-SHA256 over the timestamp and
+// body. Secrets lists the new secret first; while a rotation is under way it
+// also lists the old one, and requests carry a signature for each.
+type Signing struct {
+	Enabled         bool     `yaml:"enabled" env-default:"false"`
+	Secrets         []string `yaml:"secrets" env:"SIGNING_SECRETS"`
+	SignatureHeader string   `yaml:"signature_header" env-default:"X-Ins-Signature"`
+	TimestampHeader string   `yaml:"timestamp_header" env-default:"X-Ins-Timestamp"`
+}
+
+// SenderTransport configures the connections of the webhook sender: a client
+// certificate for mutual TLS, a CA bundle trusted next to the system roots,
+// the minimum TLS version ("1.2" or "1.3"), an HTTP proxy, which otherwise
+// comes from HTTPS_PROXY and friends, and the connection pool.
+type SenderTransport struct {
+	CertFile            string        `yaml:"cert_file" env:"SENDER_CERT_FILE"`
+	KeyFile             string        `yaml:"key_file" env:"SENDER_KEY_FILE"`
+	CAFile              string        `yaml:"ca_file" env:"SENDER_CA_FILE"`
+	MinTLSVersion       string        `yaml:"min_tls_version" env-default:"1.2"`
+	ProxyURL            string        `yaml:"proxy_url" env:"SENDER_PROXY_URL"`
+	MaxIdleConns        int           `yaml:"max_idle_conns" env-default:"100"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host" env-default:"10"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host" env-default:"0"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout" env-default:"90s"`
 }
 
 func Load() (*Config, error) {
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditSchedulerStart    = "scheduler.start"
+	AuditSchedulerStop     = "scheduler.stop"
+	AuditSchedulerTrigger  = "scheduler.trigger"
+	AuditMessageCreate     = "message.create"
+	AuditMessageRequeue    = "message.requeue"
+	AuditMessageCancel     = "message.cancel"
+	AuditMessageImport     = "message.import"
+	AuditCampaignCreate    = "campaign.create"
+	AuditCampaignPause     = "campaign.pause"
+	AuditCampaignResume    = "campaign.resume"
+	AuditTemplateCreate    = "template.create"
+	AuditTemplateDelete    = "template.delete"
+	AuditSuppressionCreate = "suppression.create"
+	AuditSuppressionUpdate = "suppression.update"
+	AuditSuppressionDelete = "suppression.delete"
+	AuditInboundReceive    = "inbound.receive"
+	AuditAPIKeyCreate      = "api_key.create"
+	AuditAPIKeyRevoke      = "api_key.revoke"
+	AuditTenantCreate      = "tenant.create"
+	AuditTenantUpdate      = "tenant.update"
+	AuditNumberForget      = "number.forget"
+)
+
+// AuditEntry records a state-changing action. Target identifies the affected
+// resource, e.g. "message:42"; Params holds the relevant request parameters.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	SourceIP  string          `json:"source_ip,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	Actor        string
+	Action       string
+	Target       string
+	CreatedFrom  *time.Time
+	CreatedUntil *time.Time
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	ScopeMessagesRead   = "messages:read"
+	ScopeMessagesWrite  = "messages:write"
+	ScopeSchedulerAdmin = "scheduler:admin"
+	ScopeKeysAdmin      = "keys:admin"
+	ScopeCallbacksWrite = "callbacks:write"
+	ScopeAuditRead      = "audit:read"
+	ScopeTenantsAdmin   = "tenants:admin"
+	ScopeReportsRead    = "reports:read"
+	ScopePrivacyAdmin   = "privacy:admin"
+)
+
+// Scopes lists every scope an API key can be granted.
+var Scopes = []string{
+	ScopeMessagesRead,
+	ScopeMessagesWrite,
+	ScopeSchedulerAdmin,
+	ScopeKeysAdmin,
+	ScopeCallbacksWrite,
+	ScopeAuditRead,
+	ScopeTenantsAdmin,
+	ScopeReportsRead,
+	ScopePrivacyAdmin,
+}
+
+// TenantScopes are the scopes a key bound to a tenant can be granted. The
+// others act on shared state such as the scheduler.
+var TenantScopes = []string{
+	ScopeMessagesRead,
+	ScopeMessagesWrite,
+	ScopeKeysAdmin,
+	ScopeReportsRead,
+}
+
+// APIKey is a client credential. Only the SHA-256 hash of the key is stored;
+// Prefix identifies the key without revealing it.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	TenantID   *int64     `json:"tenant_id,omitempty"`
+	Quota
+}
+
+// Principal is the authenticated caller of a request. A principal bound to a
+// tenant only sees that tenant's data; a nil TenantID is unrestricted.
+type Principal struct {
+	Type     string
+	ID       string
+	Name     string
+	Scopes   []string
+	TenantID *int64
+	// Quota holds the limits of an API key; other principals are unlimited.
+	Quota Quota
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type CampaignStatus string
+
+const (
+	CampaignActive CampaignStatus = "active"
+	CampaignPaused CampaignStatus = "paused"
+)
+
+// Campaign groups messages that are sent together. Messages of a paused
+// campaign are skipped by the dispatcher until it is resumed.
+type Campaign struct {
+	ID     int64          `db:"id" json:"id"`
+	Name   string         `db:"name" json:"name"`
+	Status CampaignStatus `db:"status" json:"status"`
+	// DedupWindowSeconds is the default deduplication window of the
+	// campaign's messages.
+	DedupWindowSeconds *int      `db:"dedup_window_seconds" json:"dedup_window_seconds,omitempty"`
+	TenantID           *int64    `db:"tenant_id" json:"tenant_id,omitempty"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// CampaignStats holds message counts of a campaign per status.
+type CampaignStats struct {
+	Total      int `db:"total" json:"total"`
+	Pending    int `db:"pending" json:"pending"`
+	Processing int `db:"processing" json:"processing"`
+	Sent       int `db:"sent" json:"sent"`
+	Delivered  int `db:"delivered" json:"delivered"`
+	Failed     int `db:"failed" json:"failed"`
+	Cancelled  int `db:"cancelled" json:"cancelled"`
+	Suppressed int `db:"suppressed" json:"suppressed"`
+	Duplicate  int `db:"duplicate" json:"duplicate"`
+}
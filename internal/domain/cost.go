@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+const (
+	CostGroupDay      = "day"
+	CostGroupCampaign = "campaign"
+	CostGroupTenant   = "tenant"
+	CostGroupProvider = "provider"
+)
+
+// CostGroups lists the dimensions a cost report can be grouped by.
+var CostGroups = []string{
+	CostGroupDay,
+	CostGroupCampaign,
+	CostGroupTenant,
+	CostGroupProvider,
+}
+
+// MessageCost is recorded on a message when it is sent. A nil UnitPrice
+// means the price table had no entry for the recipient.
+type MessageCost struct {
+	Provider  string
+	Segments  int
+	UnitPrice *float64
+}
+
+// CostFilter selects sent messages for a cost report. TenantID is set from
+// the caller.
+type CostFilter struct {
+	GroupBy    []string
+	From       *time.Time
+	Until      *time.Time
+	TenantID   *int64
+	CampaignID *int64
+}
+
+// CostRecord is the cost of the sent messages of one group. Dimensions the
+// report is not grouped by are nil. UnpricedSegments were sent without a
+// known price and are not part of Cost.
+type CostRecord struct {
+	Day              *time.Time `db:"day" json:"day,omitempty"`
+	CampaignID       *int64     `db:"campaign_id" json:"campaign_id,omitempty"`
+	TenantID         *int64     `db:"tenant_id" json:"tenant_id,omitempty"`
+	Provider         *string    `db:"provider" json:"provider,omitempty"`
+	Messages         int        `db:"messages" json:"messages"`
+	Segments         int        `db:"segments" json:"segments"`
+	UnpricedSegments int        `db:"unpriced_segments" json:"unpriced_segments"`
+	Cost             float64    `db:"cost" json:"cost"`
+}
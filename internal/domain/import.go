@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type ImportStatus string
+
+const (
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+)
+
+// Import is a CSV upload of recipients being enqueued in the background.
+// Columns is the header row of the file.
+type Import struct {
+	ID            int64        `json:"id"`
+	Status        ImportStatus `json:"status"`
+	Filename      string       `json:"filename"`
+	Columns       []string     `json:"columns"`
+	TemplateID    *int64       `json:"template_id,omitempty"`
+	CampaignID    *int64       `json:"campaign_id,omitempty"`
+	TenantID      *int64       `json:"tenant_id,omitempty"`
+	CreatedBy     string       `json:"created_by"`
+	TotalRows     int          `json:"total_rows"`
+	ProcessedRows int          `json:"processed_rows"`
+	AcceptedRows  int          `json:"accepted_rows"`
+	RejectedRows  int          `json:"rejected_rows"`
+	Error         *string      `json:"error,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	CompletedAt   *time.Time   `json:"completed_at,omitempty"`
+}
+
+// ImportRowError is a rejected row of an import. Line is the line of the
+// row in the uploaded file, counting the header as line 1.
+type ImportRowError struct {
+	Line   int      `json:"line"`
+	Record []string `json:"record"`
+	Reason string   `json:"reason"`
+}
+
+// ImportProgress is the state of an import after a batch of rows.
+type ImportProgress struct {
+	Processed int
+	Accepted  int
+	Rejected  int
+	Errors    []ImportRowError
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// InboundMessage is a reply received from a recipient. MessageID links it
+// to the last outbound message sent to that number.
+type InboundMessage struct {
+	ID         int64     `db:"id" json:"id"`
+	From       string    `db:"from" json:"from"`
+	To         *string   `db:"to" json:"to,omitempty"`
+	Content    string    `db:"content" json:"content"`
+	ExternalID *string   `db:"external_id" json:"external_id,omitempty"`
+	MessageID  *int64    `db:"message_id" json:"message_id,omitempty"`
+	OptOut     bool      `db:"opt_out" json:"opt_out"`
+	ReceivedAt time.Time `db:"received_at" json:"received_at"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
@@ -5,18 +5,60 @@ import "time"
 type MessageStatus string
 
 const (
-	StatusPending MessageStatus = "pending"
-	StatusSent    MessageStatus = "sent"
-	StatusFailed  MessageStatus = "failed"
+	StatusPending    MessageStatus = "pending"
+	StatusProcessing MessageStatus = "processing"
+	StatusSent       MessageStatus = "sent"
+	StatusDelivered  MessageStatus = "delivered"
+	StatusFailed     MessageStatus = "failed"
+	StatusCancelled  MessageStatus = "cancelled"
+	StatusSuppressed MessageStatus = "suppressed"
+	StatusDuplicate  MessageStatus = "duplicate"
 )
 
+// Message is an outbound SMS. ContentHash and DedupWindowSeconds drive
+// deduplication: a nil window disables it for the message. With encryption
+// at rest the stored ContentHash is keyed rather than a plain SHA-256; it is
+// never serialized, as a plain hash of short content gives the content away.
+// A nil TenantID belongs to the default tenant. Provider, Segments and UnitPrice are set
+// when the message is sent.
 type Message struct {
-	ID         int64         `db:"id"`
-	To         string        `db:"to"`
-	Content    string        `db:"content"`
-	Status     MessageStatus `db:"status"`
-	SentAt     *time.Time    `db:"sent_at"`
-	ExternalID *string       `db:"external_id"`
-	CreatedAt  time.Time     `db:"created_at"`
-	UpdatedAt  time.Time     `db:"updated_at"`
+	ID                 int64         `db:"id"`
+	To                 string        `db:"to"`
+	Content            string        `db:"content"`
+	Status             MessageStatus `db:"status"`
+	SentAt             *time.Time    `db:"sent_at"`
+	ExternalID         *string       `db:"external_id"`
+	Attempts           int           `db:"attempts"`
+	LastError          *string       `db:"last_error"`
+	CampaignID         *int64        `db:"campaign_id"`
+	TemplateID         *int64        `db:"template_id"`
+	NotBefore          *time.Time    `db:"not_before"`
+	ContentHash        *string       `db:"content_hash" json:"-"`
+	DedupWindowSeconds *int          `db:"dedup_window_seconds"`
+	DuplicateOf        *int64        `db:"duplicate_of"`
+	TenantID           *int64        `db:"tenant_id"`
+	Provider           *string       `db:"provider"`
+	Segments           *int          `db:"segments"`
+	UnitPrice          *float64      `db:"unit_price"`
+	CreatedAt          time.Time     `db:"created_at"`
+	UpdatedAt          time.Time     `db:"updated_at"`
+}
+
+// MessageFilter narrows down message queries. Zero values are ignored.
+// TenantID scopes the query to a tenant; it is set from the caller rather
+// than from request bodies.
+type MessageFilter struct {
+	Status       MessageStatus `json:"status,omitempty"`
+	To           string        `json:"to,omitempty"`
+	CampaignID   *int64        `json:"campaign_id,omitempty"`
+	CreatedFrom  *time.Time    `json:"created_from,omitempty"`
+	CreatedUntil *time.Time    `json:"created_until,omitempty"`
+	TenantID     *int64        `json:"-"`
+}
+
+// IsEmpty reports whether the filter selects nothing narrower than the
+// caller's tenant.
+func (f MessageFilter) IsEmpty() bool {
+	f.TenantID = nil
+	return f == MessageFilter{}
 }
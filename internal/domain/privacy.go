@@ -0,0 +1,15 @@
+package domain
+
+// ForgottenNumber replaces the phone number of anonymized rows.
+const ForgottenNumber = "forgotten"
+
+// ForgetResult counts the rows anonymized for a phone number. Cancelled
+// pending messages are counted in Messages too.
+type ForgetResult struct {
+	Messages         int `json:"messages"`
+	Cancelled        int `json:"cancelled"`
+	ArchivedMessages int `json:"archived_messages"`
+	InboundMessages  int `json:"inbound_messages"`
+	ImportErrors     int `json:"import_errors"`
+	AuditEntries     int `json:"audit_entries"`
+}
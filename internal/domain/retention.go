@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+const (
+	RetentionArchive = "archive"
+	RetentionDelete  = "delete"
+)
+
+// RetentionRun reports what one run of the retention job purged.
+// Messages purged before a failure are still counted.
+type RetentionRun struct {
+	ID         int64                 `json:"id"`
+	Mode       string                `json:"mode"`
+	Cutoff     time.Time             `json:"cutoff"`
+	Purged     int                   `json:"purged"`
+	ByStatus   map[MessageStatus]int `json:"by_status"`
+	Batches    int                   `json:"batches"`
+	Error      *string               `json:"error,omitempty"`
+	StartedAt  time.Time             `json:"started_at"`
+	FinishedAt time.Time             `json:"finished_at"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// SchedulerState is the desired scheduler state shared by all replicas.
+type SchedulerState struct {
+	Running   bool      `db:"running"`
+	UpdatedBy string    `db:"updated_by"`
+	Reason    *string   `db:"reason"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
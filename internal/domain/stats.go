@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+const (
+	StatsGroupHour     = "hour"
+	StatsGroupDay      = "day"
+	StatsGroupCampaign = "campaign"
+	StatsGroupTenant   = "tenant"
+)
+
+// StatsGroups lists the dimensions statistics can be grouped by.
+var StatsGroups = []string{
+	StatsGroupHour,
+	StatsGroupDay,
+	StatsGroupCampaign,
+	StatsGroupTenant,
+}
+
+// StatsFilter selects the messages created within [From, Until). An empty
+// GroupBy only reports totals. TenantID is set from the caller.
+type StatsFilter struct {
+	From     time.Time
+	Until    time.Time
+	GroupBy  string
+	TenantID *int64
+}
+
+// StatusCounts counts messages by status.
+type StatusCounts struct {
+	Pending    int `db:"pending" json:"pending"`
+	Processing int `db:"processing" json:"processing"`
+	Sent       int `db:"sent" json:"sent"`
+	Delivered  int `db:"delivered" json:"delivered"`
+	Failed     int `db:"failed" json:"failed"`
+	Cancelled  int `db:"cancelled" json:"cancelled"`
+	Suppressed int `db:"suppressed" json:"suppressed"`
+	Duplicate  int `db:"duplicate" json:"duplicate"`
+}
+
+// Total is the number of messages counted.
+func (c StatusCounts) Total() int {
+	return c.Pending + c.Processing + c.Sent + c.Delivered + c.Failed + c.Cancelled + c.Suppressed + c.Duplicate
+}
+
+// Stats describes the messages of one group, or of the whole range when
+// Bucket, CampaignID and TenantID are all nil. Latencies are the seconds from
+// created_at to sent_at and are nil when nothing was sent.
+type Stats struct {
+	Bucket            *time.Time `db:"bucket" json:"bucket,omitempty"`
+	CampaignID        *int64     `db:"campaign_id" json:"campaign_id,omitempty"`
+	TenantID          *int64     `db:"tenant_id" json:"tenant_id,omitempty"`
+	SentPerMinute     float64    `db:"-" json:"sent_per_minute"`
+	SentPerHour       float64    `db:"-" json:"sent_per_hour"`
+	FailureRate       float64    `db:"-" json:"failure_rate"`
+	LatencyP50Seconds *float64   `db:"latency_p50" json:"latency_p50_seconds"`
+	LatencyP95Seconds *float64   `db:"latency_p95" json:"latency_p95_seconds"`
+	StatusCounts      `json:"counts"`
+}
+
+// Backlog is the pending queue at the time of the request.
+type Backlog struct {
+	Pending          int        `db:"pending" json:"pending"`
+	OldestPendingAt  *time.Time `db:"oldest_pending_at" json:"oldest_pending_at,omitempty"`
+	OldestAgeSeconds float64    `db:"-" json:"oldest_pending_age_seconds"`
+}
+
+// StatsReport is the answer to a statistics request.
+type StatsReport struct {
+	From    time.Time `json:"from"`
+	Until   time.Time `json:"until"`
+	GroupBy string    `json:"group_by,omitempty"`
+	Totals  Stats     `json:"totals"`
+	Groups  []Stats   `json:"groups,omitempty"`
+	Backlog Backlog   `json:"backlog"`
+}
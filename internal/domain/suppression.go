@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+const (
+	SuppressionSourceAPI     = "api"
+	SuppressionSourceInbound = "inbound"
+)
+
+// Suppression is a recipient that must not receive messages, e.g. because
+// they replied STOP.
+type Suppression struct {
+	Number    string    `db:"number" json:"number"`
+	Reason    string    `db:"reason" json:"reason"`
+	Source    string    `db:"source" json:"source"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Template is a reusable message body. Placeholders use Go text/template
+// syntax, e.g. "Hello {{.name}}". A nil TenantID belongs to the default
+// tenant.
+type Template struct {
+	ID        int64     `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Locale    string    `db:"locale" json:"locale"`
+	Body      string    `db:"body" json:"body"`
+	TenantID  *int64    `db:"tenant_id" json:"tenant_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
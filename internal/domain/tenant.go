@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Tenant is a business unit with its own provider credentials. Messages
+// without a tenant are sent with the application webhook settings. An empty
+// Provider prices the tenant's messages as the application provider.
+type Tenant struct {
+	ID             int64     `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	WebhookURL     string    `db:"webhook_url" json:"webhook_url"`
+	WebhookAuthKey string    `db:"webhook_auth_key" json:"-"`
+	Provider       string    `db:"provider" json:"provider,omitempty"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	Quota
+}
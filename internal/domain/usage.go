@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+const (
+	QuotaSubjectTenant = "tenant"
+	QuotaSubjectAPIKey = "api_key"
+
+	UsagePeriodDay   = "day"
+	UsagePeriodMonth = "month"
+)
+
+// Quota limits the segments a tenant or API key may enqueue per UTC day and
+// calendar month. A nil limit is unlimited.
+type Quota struct {
+	Daily   *int `db:"daily_quota" json:"daily_quota,omitempty"`
+	Monthly *int `db:"monthly_quota" json:"monthly_quota,omitempty"`
+}
+
+// UsageCharge is the usage of one enqueued message.
+type UsageCharge struct {
+	TenantID *int64
+	APIKeyID *int64
+	Messages int
+	Segments int
+	At       time.Time
+}
+
+// QuotaUsage is the state of one quota after a charge, or before a charge
+// that was rejected.
+type QuotaUsage struct {
+	Subject   string    `json:"subject"`
+	SubjectID int64     `json:"subject_id"`
+	Period    string    `json:"period"`
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	ResetAt   time.Time `json:"reset_at"`
+	Exceeded  bool      `json:"exceeded"`
+}
+
+func (q QuotaUsage) Remaining() int {
+	return max(q.Limit-q.Used, 0)
+}
+
+// UsageFilter selects ledger entries. TenantID is set from the caller.
+type UsageFilter struct {
+	Period   string
+	From     *time.Time
+	Until    *time.Time
+	TenantID *int64
+	APIKeyID *int64
+}
+
+// UsageRecord is the consumption of a tenant and API key in one period.
+type UsageRecord struct {
+	PeriodStart time.Time `db:"period_start" json:"period_start"`
+	TenantID    *int64    `db:"tenant_id" json:"tenant_id,omitempty"`
+	APIKeyID    *int64    `db:"api_key_id" json:"api_key_id,omitempty"`
+	Messages    int       `db:"messages" json:"messages"`
+	Segments    int       `db:"segments" json:"segments"`
+}
@@ -0,0 +1,205 @@
+// Package keyring implements envelope encryption with keys from a local
+// keyfile. Every value is sealed with its own random data encryption key
+// (DEK); the DEK is stored next to the value, wrapped by a key encryption key
+// (KEK) from the keyfile. Rotating the KEK only requires rewrapping DEKs.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// keySize is the size of KEKs and DEKs; both are AES-256 keys.
+const keySize = 32
+
+// hashKeyLabel derives the key of Hash from the active KEK, so the KEK itself
+// is only ever used for AES.
+const hashKeyLabel = "insider content hash"
+
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring holds the KEKs by key ID. New values are sealed with the active
+// key; the others are kept to open values sealed before a rotation.
+type Keyring struct {
+	active  string
+	keys    map[string]cipher.AEAD
+	hashKey []byte
+}
+
+// Load reads a keyfile of the form
+//
+//	{"active": "2026-10", "keys": {"2026-10": "<base64>", "2026-01": "<base64>"}}
+//
+// where every key is 32 random bytes, base64 encoded.
+func Load(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keyfile: %w", err)
+	}
+
+	var doc struct {
+		Active string            `json:"active"`
+		Keys   map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode keyfile: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(doc.Keys))
+	for id, encoded := range doc.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	return New(doc.Active, keys)
+}
+
+// New builds a keyring from raw keys by key ID. active must be one of them.
+func New(active string, keys map[string][]byte) (*Keyring, error) {
+	k := &Keyring{active: active, keys: make(map[string]cipher.AEAD, len(keys))}
+
+	for id, key := range keys {
+		if id == "" {
+			return nil, errors.New("key ids must not be empty")
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		k.keys[id] = aead
+	}
+
+	if _, ok := k.keys[active]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the keyring", active)
+	}
+
+	mac := hmac.New(sha256.New, keys[active])
+	mac.Write([]byte(hashKeyLabel))
+	k.hashKey = mac.Sum(nil)
+
+	return k, nil
+}
+
+// ActiveKeyID returns the ID of the key new values are sealed with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Hash returns an HMAC-SHA256 of data keyed by the active key. Equal values
+// hash equally, so it can stand in for a plain hash of a secret value
+// without letting anyone who reads the hash guess the value. Hashes change
+// with the active key.
+func (k *Keyring) Hash(data []byte) []byte {
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Seal encrypts plaintext with a new DEK and returns the ciphertext, the ID
+// of the KEK and the DEK wrapped by it.
+func (k *Keyring) Seal(plaintext []byte) (ciphertext []byte, keyID string, dek []byte, err error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, "", nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ciphertext, err = seal(aead, plaintext, nil)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// The key ID is authenticated with the DEK, so a DEK cannot be passed
+	// off as wrapped by another key.
+	dek, err = seal(k.keys[k.active], key, []byte(k.active))
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return ciphertext, k.active, dek, nil
+}
+
+// Open decrypts a value sealed by Seal.
+func (k *Keyring) Open(ciphertext []byte, keyID string, dek []byte) ([]byte, error) {
+	key, err := k.unwrap(keyID, dek)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, nil)
+}
+
+// Rewrap wraps a DEK with the active key, leaving the value it sealed
+// untouched.
+func (k *Keyring) Rewrap(keyID string, dek []byte) (string, []byte, error) {
+	key, err := k.unwrap(keyID, dek)
+	if err != nil {
+		return "", nil, err
+	}
+
+	wrapped, err := seal(k.keys[k.active], key, []byte(k.active))
+	if err != nil {
+		return "", nil, err
+	}
+	return k.active, wrapped, nil
+}
+
+func (k *Keyring) unwrap(keyID string, dek []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+
+	key, err := open(aead, dek, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce, which is prepended to the
+// ciphertext.
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additional []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additional)
+}
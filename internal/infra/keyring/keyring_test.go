@@ -0,0 +1,224 @@
+package keyring_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/LevanPro/insider/internal/infra/keyring"
+)
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestSealOpen(t *testing.T) {
+	k, err := keyring.New("k1", map[string][]byte{"k1": key(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, keyID, dek, err := k.Seal([]byte("Your code is 123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != "k1" {
+		t.Fatalf("key id = %q, want k1", keyID)
+	}
+	if bytes.Contains(ciphertext, []byte("123456")) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+
+	plaintext, err := k.Open(ciphertext, keyID, dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "Your code is 123456" {
+		t.Fatalf("plaintext = %q", plaintext)
+	}
+
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, err := k.Open(ciphertext, keyID, dek); err == nil {
+		t.Fatal("tampered ciphertext opened")
+	}
+}
+
+func TestSealLongMessage(t *testing.T) {
+	k, err := keyring.New("k1", map[string][]byte{"k1": key(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 160 characters of up to four bytes each, stored base64 encoded.
+	content := strings.Repeat("şğ€😀", 40)
+	if n := utf8.RuneCountInString(content); n != 160 {
+		t.Fatalf("content has %d runes, want 160", n)
+	}
+
+	ciphertext, keyID, dek, err := k.Seal([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := base64.StdEncoding.EncodeToString(ciphertext)
+	if len(stored) <= 160 {
+		t.Fatalf("stored content is %d bytes; sealed content should not fit the former VARCHAR(160)", len(stored))
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := k.Open(decoded, keyID, dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != content {
+		t.Fatalf("plaintext = %q, want %q", plaintext, content)
+	}
+}
+
+func TestHash(t *testing.T) {
+	k1, err := keyring.New("k1", map[string][]byte{"k1": key(1), "k2": key(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := keyring.New("k2", map[string][]byte{"k1": key(1), "k2": key(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := []byte("Your code is 123456")
+	if !bytes.Equal(k1.Hash(code), k1.Hash(code)) {
+		t.Fatal("hash is not deterministic")
+	}
+	if bytes.Equal(k1.Hash(code), k1.Hash([]byte("Your code is 123457"))) {
+		t.Fatal("different values hash equally")
+	}
+	if bytes.Equal(k1.Hash(code), k2.Hash(code)) {
+		t.Fatal("hash does not depend on the active key")
+	}
+	plain := sha256.Sum256(code)
+	if bytes.Equal(k1.Hash(code), plain[:]) {
+		t.Fatal("hash is a plain SHA-256")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old, err := keyring.New("k1", map[string][]byte{"k1": key(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, keyID, dek, err := old.Seal([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := keyring.New("k2", map[string][]byte{"k1": key(1), "k2": key(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rotated.Open(ciphertext, keyID, dek); err != nil {
+		t.Fatalf("open with retired key: %v", err)
+	}
+
+	newKeyID, newDEK, err := rotated.Rewrap(keyID, dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newKeyID != "k2" {
+		t.Fatalf("rewrapped key id = %q, want k2", newKeyID)
+	}
+
+	// Without the retired key only the rewrapped DEK opens the value.
+	current, err := keyring.New("k2", map[string][]byte{"k2": key(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := current.Open(ciphertext, keyID, dek); !errors.Is(err, keyring.ErrUnknownKey) {
+		t.Fatalf("err = %v, want ErrUnknownKey", err)
+	}
+	plaintext, err := current.Open(ciphertext, newKeyID, newDEK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "hello" {
+		t.Fatalf("plaintext = %q", plaintext)
+	}
+
+	// A DEK wrapped by k2 is not accepted under another key ID.
+	if _, err := rotated.Open(ciphertext, "k1", newDEK); err == nil {
+		t.Fatal("opened with a mislabelled data key")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     map[string]any
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			doc: map[string]any{
+				"active": "k2",
+				"keys": map[string]string{
+					"k1": base64.StdEncoding.EncodeToString(key(1)),
+					"k2": base64.StdEncoding.EncodeToString(key(2)),
+				},
+			},
+		},
+		{
+			name: "missing active key",
+			doc: map[string]any{
+				"active": "k3",
+				"keys":   map[string]string{"k1": base64.StdEncoding.EncodeToString(key(1))},
+			},
+			wantErr: true,
+		},
+		{
+			name: "short key",
+			doc: map[string]any{
+				"active": "k1",
+				"keys":   map[string]string{"k1": base64.StdEncoding.EncodeToString(key(1)[:16])},
+			},
+			wantErr: true,
+		},
+		{
+			name: "not base64",
+			doc: map[string]any{
+				"active": "k1",
+				"keys":   map[string]string{"k1": "not base64!"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "keys.json")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			k, err := keyring.Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && k.ActiveKeyID() != "k2" {
+				t.Fatalf("active = %q, want k2", k.ActiveKeyID())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Elector elects a single leader among replicas using a Postgres session
+// level advisory lock. The lock belongs to the connection that acquired it,
+// so the elector keeps that connection pinned for as long as it leads.
+type Elector struct {
+	db  *sqlx.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewElector(db *sqlx.DB, key int64) *Elector {
+	return &Elector{
+		db:  db,
+		key: key,
+	}
+}
+
+// Elect tries to acquire leadership, or confirms that it is still held.
+func (e *Elector) Elect(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		if err := e.conn.PingContext(ctx); err != nil {
+			// The server releases the lock together with the session.
+			e.conn.Close()
+			e.conn = nil
+			return false, fmt.Errorf("leader connection lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, e.key).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("try advisory lock: %w", err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	e.conn = conn
+
+	return true, nil
+}
+
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.conn != nil
+}
+
+// Resign releases leadership so another replica can take over.
+func (e *Elector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		e.conn.Close()
+		e.conn = nil
+	}()
+
+	if _, err := e.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, e.key); err != nil {
+		return fmt.Errorf("advisory unlock: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// visibleDigits is how many trailing digits of a masked number stay readable.
+const visibleDigits = 3
+
+// numberPattern finds E.164 phone numbers in free text such as request URIs.
+// The leading plus, possibly URL encoded, keeps IDs and timestamps readable.
+var numberPattern = regexp.MustCompile(`(\+|%2[Bb])\d{7,}`)
+
+// MaskNumber replaces every digit of a phone number but the last three.
+func MaskNumber(number string) string {
+	seen := 0
+	masked := []byte(number)
+	for i := len(masked) - 1; i >= 0; i-- {
+		if masked[i] < '0' || masked[i] > '9' {
+			continue
+		}
+		seen++
+		if seen > visibleDigits {
+			masked[i] = '*'
+		}
+	}
+	return string(masked)
+}
+
+// MaskNumbers masks the phone numbers found in text.
+func MaskNumbers(text string) string {
+	return numberPattern.ReplaceAllStringFunc(text, func(number string) string {
+		// Keep an encoded plus intact rather than masking its hex digits.
+		prefix := ""
+		if strings.HasPrefix(number, "%") {
+			prefix, number = number[:3], number[3:]
+		}
+		return prefix + MaskNumber(number)
+	})
+}
+
+// Redact returns a logger that masks the string values logged under keys,
+// e.g. "to", with MaskNumber.
+func Redact(log *zap.SugaredLogger, keys ...string) *zap.SugaredLogger {
+	redacted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		redacted[key] = true
+	}
+
+	return log.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{Core: core, keys: redacted}
+	})).Sugar()
+}
+
+type redactingCore struct {
+	zapcore.Core
+	keys map[string]bool
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redact(fields)), keys: c.keys}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+func (c *redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, f := range fields {
+		if f.Type != zapcore.StringType || !c.keys[f.Key] {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]zapcore.Field(nil), fields...)
+		}
+		redacted[i] = zap.String(f.Key, MaskNumber(f.String))
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
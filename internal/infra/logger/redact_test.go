@@ -0,0 +1,68 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/LevanPro/insider/internal/infra/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMaskNumber(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected string
+	}{
+		{"+905551234567", "+*********567"},
+		{"+90 555 123 45 67", "+** *** *** *5 67"},
+		{"12", "12"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := logger.MaskNumber(tt.number); got != tt.expected {
+			t.Errorf("MaskNumber(%q) = %q, expected %q", tt.number, got, tt.expected)
+		}
+	}
+}
+
+func TestMaskNumbers(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"/api/v1/suppressions/+905551234567", "/api/v1/suppressions/+*********567"},
+		{"/api/v1/messages/failed?to=%2B905551234567&limit=50", "/api/v1/messages/failed?to=%2B*********567&limit=50"},
+		{"/api/v1/messages/42", "/api/v1/messages/42"},
+		{"/api/v1/imports/12345678901/errors", "/api/v1/imports/12345678901/errors"},
+		{"/api/v1/messages/sent?created_from=1700000000&to=+905551234567", "/api/v1/messages/sent?created_from=1700000000&to=+*********567"},
+	}
+
+	for _, tt := range tests {
+		if got := logger.MaskNumbers(tt.text); got != tt.expected {
+			t.Errorf("MaskNumbers(%q) = %q, expected %q", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := logger.Redact(zap.New(core).Sugar(), "to")
+
+	log.With("to", "+905551234567").Infow("with")
+	log.Infow("fields", "to", "+905551234567", "messageID", 7, "other", "+905551234567")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, expected 2", len(entries))
+	}
+	for _, entry := range entries {
+		if got := entry.ContextMap()["to"]; got != "+*********567" {
+			t.Errorf("%s: to = %v, expected it masked", entry.Message, got)
+		}
+	}
+	if got := entries[1].ContextMap()["other"]; got != "+905551234567" {
+		t.Errorf("other = %v, expected only listed keys to be masked", got)
+	}
+}
@@ -0,0 +1,243 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet holds the public keys of a JWKS document by key ID.
+type KeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+// ParseKeySet parses a JWKS document. Keys that are not RSA or EC signing
+// keys are ignored.
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	set := &KeySet{keys: make(map[string]crypto.PublicKey)}
+
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			set.keys[k.Kid] = key
+		}
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("jwks contains no usable signing keys")
+	}
+
+	return set, nil
+}
+
+// Key returns the key with the given ID. An empty kid matches the only key
+// of a single-key set.
+func (s *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// KeySource provides the current key set.
+type KeySource interface {
+	KeySet(ctx context.Context, refresh bool) (*KeySet, error)
+}
+
+// FileKeySource reads a static JWKS document from disk once.
+type FileKeySource struct {
+	set *KeySet
+}
+
+func NewFileKeySource(path string) (*FileKeySource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwks file: %w", err)
+	}
+
+	set, err := ParseKeySet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileKeySource{set: set}, nil
+}
+
+func (s *FileKeySource) KeySet(ctx context.Context, refresh bool) (*KeySet, error) {
+	return s.set, nil
+}
+
+// minRefreshInterval rate limits refreshes triggered by unknown key IDs.
+const minRefreshInterval = time.Minute
+
+// URLKeySource fetches a JWKS document over HTTP and caches it. It refreshes
+// after the refresh interval, or earlier when a token uses an unknown key.
+type URLKeySource struct {
+	url        string
+	httpClient *http.Client
+	refresh    time.Duration
+
+	mu        sync.Mutex
+	set       *KeySet
+	fetchedAt time.Time
+}
+
+func NewURLKeySource(url string, refresh time.Duration) *URLKeySource {
+	return &URLKeySource{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		refresh: refresh,
+	}
+}
+
+func (s *URLKeySource) KeySet(ctx context.Context, refresh bool) (*KeySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.fetchedAt)
+	stale := s.set == nil || age > s.refresh || (refresh && age > minRefreshInterval)
+	if !stale {
+		return s.set, nil
+	}
+
+	set, err := s.fetch(ctx)
+	if err != nil {
+		if s.set != nil {
+			// Keep serving the cached keys while the issuer is unavailable.
+			return s.set, nil
+		}
+		return nil, err
+	}
+
+	s.set = set
+	s.fetchedAt = time.Now()
+
+	return set, nil
+}
+
+func (s *URLKeySource) fetch(ctx context.Context) (*KeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch jwks: unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+
+	return ParseKeySet(data)
+}
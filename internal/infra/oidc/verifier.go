@@ -0,0 +1,245 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	ErrInvalidIssuer    = errors.New("invalid token issuer")
+	ErrInvalidAudience  = errors.New("invalid token audience")
+)
+
+// algorithms are the accepted signing algorithms. "none" and HMAC algorithms
+// are deliberately absent.
+var algorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Claims are the decoded claims of a verified token.
+type Claims map[string]any
+
+type Verifier struct {
+	keys     KeySource
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier verifies tokens signed by keys of the source that were issued by
+// issuer for audience.
+func NewVerifier(keys KeySource, issuer, audience string, leeway time.Duration) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		leeway:   leeway,
+		now:      time.Now,
+	}
+}
+
+// Verify checks the signature and registered claims of a compact JWS token.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(algorithms, header.Alg) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	set, err := v.keys.KeySet(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := set.Key(kid)
+	if errors.Is(err, ErrUnknownKey) {
+		// The issuer may have rotated its keys.
+		if set, err = v.keys.KeySet(ctx, true); err != nil {
+			return nil, err
+		}
+		key, err = set.Key(kid)
+	}
+
+	return key, err
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.now()
+
+	exp, ok := claims.time("exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrMalformedToken)
+	}
+	if now.After(exp.Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+
+	if nbf, ok := claims.time("nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return ErrTokenNotYetValid
+	}
+
+	if claims.String("iss") != v.issuer {
+		return ErrInvalidIssuer
+	}
+
+	if !slices.Contains(claims.Strings("aud"), v.audience) {
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+// String returns a string claim, or "" if it is missing or not a string.
+func (c Claims) String(name string) string {
+	s, _ := c.lookup(name).(string)
+	return s
+}
+
+// Strings returns a claim that is either a string or a list of strings.
+// Dots in name address nested objects, e.g. "realm_access.roles".
+func (c Claims) Strings(name string) []string {
+	switch v := c.lookup(name).(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func (c Claims) lookup(name string) any {
+	var current any = map[string]any(c)
+	for _, part := range strings.Split(name, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = obj[part]
+	}
+	return current
+}
+
+func (c Claims) time(name string) (time.Time, bool) {
+	n, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(n), 0), true
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return ErrInvalidSignature
+		}
+		if err := rsa.VerifyPKCS1v15(k, hash, digest, signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return ErrInvalidSignature
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	default:
+		return ErrInvalidSignature
+	}
+}
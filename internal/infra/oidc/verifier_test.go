@@ -0,0 +1,187 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/infra/oidc"
+)
+
+func encode(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeKeySet(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) string {
+	t.Helper()
+
+	jwks := map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"use": "sig",
+				"n":   b64(rsaKey.N.Bytes()),
+				"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec-1",
+				"crv": "P-256",
+				"x":   b64(ecKey.X.FillBytes(make([]byte, 32))),
+				"y":   b64(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+		},
+	}
+
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	input := encode(t, map[string]string{"alg": "RS256", "kid": kid}) + "." + encode(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input + "." + b64(sig)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	input := encode(t, map[string]string{"alg": "ES256", "kid": kid}) + "." + encode(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return input + "." + b64(sig)
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := oidc.NewFileKeySource(writeKeySet(t, rsaKey, ecKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := oidc.NewVerifier(keys, "https://idp.example.com", "insider", time.Minute)
+
+	now := time.Now().Unix()
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":   "https://idp.example.com",
+			"aud":   []string{"insider", "other"},
+			"sub":   "user-1",
+			"exp":   now + 300,
+			"roles": []string{"sms-admin"},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"rsa", signRS256(t, rsaKey, "rsa-1", claims(nil)), nil},
+		{"ec", signES256(t, ecKey, "ec-1", claims(nil)), nil},
+		{"string audience", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"aud": "insider"})), nil},
+		{"within leeway", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"exp": now - 30})), nil},
+		{"expired", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"exp": now - 120})), oidc.ErrTokenExpired},
+		{"missing exp", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"exp": nil})), oidc.ErrMalformedToken},
+		{"not yet valid", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"nbf": now + 300})), oidc.ErrTokenNotYetValid},
+		{"wrong issuer", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"iss": "https://evil.example.com"})), oidc.ErrInvalidIssuer},
+		{"wrong audience", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"aud": "other"})), oidc.ErrInvalidAudience},
+		{"missing audience", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"aud": nil})), oidc.ErrInvalidAudience},
+		{"missing issuer", signRS256(t, rsaKey, "rsa-1", claims(map[string]any{"iss": nil})), oidc.ErrInvalidIssuer},
+		{"unknown key", signRS256(t, rsaKey, "rsa-2", claims(nil)), oidc.ErrUnknownKey},
+		{"foreign signature", signRS256(t, otherKey, "rsa-1", claims(nil)), oidc.ErrInvalidSignature},
+		{"alg none", encode(t, map[string]string{"alg": "none"}) + "." + encode(t, claims(nil)) + ".", oidc.ErrUnsupportedAlg},
+		{"malformed", "not-a-token", oidc.ErrMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(context.Background(), tt.token)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Verify() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClaims(t *testing.T) {
+	claims := oidc.Claims{
+		"email": "ops@example.com",
+		"realm_access": map[string]any{
+			"roles": []any{"sms-operator", "offline_access"},
+		},
+		"group": "sms-admin",
+	}
+
+	if got := claims.String("email"); got != "ops@example.com" {
+		t.Errorf("String(email) = %q", got)
+	}
+	if got := claims.Strings("realm_access.roles"); len(got) != 2 || got[0] != "sms-operator" {
+		t.Errorf("Strings(realm_access.roles) = %v", got)
+	}
+	if got := claims.Strings("group"); len(got) != 1 || got[0] != "sms-admin" {
+		t.Errorf("Strings(group) = %v", got)
+	}
+	if got := claims.Strings("missing.path"); got != nil {
+		t.Errorf("Strings(missing.path) = %v, expected nil", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +13,7 @@ type CallbackFn func(context.Context) error
 var (
 	ErrAlreadyRunning = errors.New("scheduler is already running")
 	ErrAlreadyStopped = errors.New("scheduler is already stopped")
+	ErrBusy           = errors.New("scheduler is busy with another run")
 )
 
 type Scheduler struct {
@@ -23,6 +25,9 @@ type Scheduler struct {
 	ticker  *time.Ticker
 	quit    chan struct{}
 	running bool
+
+	// busy guards against overlapping runs of ticks and manual triggers.
+	busy atomic.Bool
 }
 
 func NewScheduler(callBackFn CallbackFn, interval time.Duration, startImmediately bool) *Scheduler {
@@ -53,13 +58,13 @@ func (s *Scheduler) Start() error {
 func (s *Scheduler) run() {
 
 	if s.startImmediately {
-		_ = s.callBackFn(context.Background())
+		_ = s.RunOnce(context.Background(), s.callBackFn)
 	}
 
 	for {
 		select {
 		case <-s.ticker.C:
-			_ = s.callBackFn(context.Background())
+			_ = s.RunOnce(context.Background(), s.callBackFn)
 		case <-s.quit:
 			s.ticker.Stop()
 			return
@@ -67,6 +72,17 @@ func (s *Scheduler) run() {
 	}
 }
 
+// RunOnce executes fn out of band. It shares the overlap guard with the
+// ticker, so it returns ErrBusy when another run is still in progress.
+func (s *Scheduler) RunOnce(ctx context.Context, fn CallbackFn) error {
+	if !s.busy.CompareAndSwap(false, true) {
+		return ErrBusy
+	}
+	defer s.busy.Store(false)
+
+	return fn(ctx)
+}
+
 func (s *Scheduler) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
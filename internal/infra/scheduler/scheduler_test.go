@@ -190,3 +190,53 @@ func TestIsRunning(t *testing.T) {
 		t.Error("Should not be running after Stop")
 	}
 }
+
+func TestRunOnce(t *testing.T) {
+	mock := &mockCallback{}
+	s := scheduler.NewScheduler(mock.Fn, testInterval, false)
+
+	if err := s.RunOnce(context.Background(), mock.Fn); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	if mock.GetCount() != 1 {
+		t.Errorf("Expected count 1 after RunOnce, got %d", mock.GetCount())
+	}
+	if s.IsRunning() {
+		t.Error("RunOnce should not start the scheduler")
+	}
+}
+
+func TestRunOnceBusy(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	s := scheduler.NewScheduler(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}, time.Hour, true)
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer s.Stop()
+
+	<-started
+
+	mock := &mockCallback{}
+	err := s.RunOnce(context.Background(), mock.Fn)
+	if err == nil || err.Error() != scheduler.ErrBusy.Error() {
+		t.Errorf("Expected ErrBusy while a tick is running, got: %v", err)
+	}
+	if mock.GetCount() != 0 {
+		t.Errorf("Expected overlapping run to be skipped, got %d executions", mock.GetCount())
+	}
+
+	close(release)
+	time.Sleep(testInterval)
+
+	if err := s.RunOnce(context.Background(), mock.Fn); err != nil {
+		t.Errorf("Expected RunOnce to succeed after the tick finished, got: %v", err)
+	}
+}
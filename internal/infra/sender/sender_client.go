@@ -25,16 +25,39 @@ type Client struct {
 	httpClient *http.Client
 	url        string
 	apiKey     string
+	signing    *Signing
 }
 
-func NewClient(url, apiKey string) *Client {
-	return &Client{
+// Option configures a Client.
+type Option func(*Client)
+
+// WithSigning signs every request with s in addition to the auth key header.
+func WithSigning(s Signing) Option {
+	return func(c *Client) {
+		c.signing = &s
+	}
+}
+
+// WithTransport sends requests over t instead of the default transport, see
+// NewTransport.
+func WithTransport(t http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = t
+	}
+}
+
+func NewClient(url, apiKey string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second,
 		},
 		url:    url,
 		apiKey: apiKey,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) Send(ctx context.Context, to, content string) (*service.SendResponse, error) {
@@ -55,6 +78,9 @@ func (c *Client) Send(ctx context.Context, to, content string) (*service.SendRes
 	if c.apiKey != "" {
 		req.Header.Set("x-ins-auth-key", c.apiKey)
 	}
+	if c.signing != nil {
+		c.signing.Sign(req.Header, bodyBytes, time.Now())
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
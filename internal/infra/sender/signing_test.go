@@ -0,0 +1,136 @@
+package sender_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/infra/sender"
+)
+
+func TestSigningVerify(t *testing.T) {
+	body := []byte(`{"to":"+905551234567","content":"hello"}`)
+	signedAt := time.Unix(1_790_000_000, 0)
+
+	tests := []struct {
+		name     string
+		signer   sender.Signing
+		verifier sender.Signing
+		body     []byte
+		now      time.Time
+		want     error
+	}{
+		{
+			name:     "valid",
+			signer:   sender.Signing{Secrets: []string{"new"}},
+			verifier: sender.Signing{Secrets: []string{"new"}},
+			body:     body,
+			now:      signedAt.Add(time.Minute),
+		},
+		{
+			name:     "receiver still on the old secret",
+			signer:   sender.Signing{Secrets: []string{"new", "old"}},
+			verifier: sender.Signing{Secrets: []string{"old"}},
+			body:     body,
+			now:      signedAt,
+		},
+		{
+			name:     "sender still on the old secret",
+			signer:   sender.Signing{Secrets: []string{"old"}},
+			verifier: sender.Signing{Secrets: []string{"new", "old"}},
+			body:     body,
+			now:      signedAt,
+		},
+		{
+			name:     "custom headers",
+			signer:   sender.Signing{Secrets: []string{"new"}, SignatureHeader: "X-Signature", TimestampHeader: "X-Timestamp"},
+			verifier: sender.Signing{Secrets: []string{"new"}, SignatureHeader: "X-Signature", TimestampHeader: "X-Timestamp"},
+			body:     body,
+			now:      signedAt,
+		},
+		{
+			name:     "headers differ",
+			signer:   sender.Signing{Secrets: []string{"new"}, SignatureHeader: "X-Signature"},
+			verifier: sender.Signing{Secrets: []string{"new"}},
+			body:     body,
+			now:      signedAt,
+			want:     sender.ErrMissingSignature,
+		},
+		{
+			name:     "unknown secret",
+			signer:   sender.Signing{Secrets: []string{"other"}},
+			verifier: sender.Signing{Secrets: []string{"new", "old"}},
+			body:     body,
+			now:      signedAt,
+			want:     sender.ErrInvalidSignature,
+		},
+		{
+			name:     "tampered body",
+			signer:   sender.Signing{Secrets: []string{"new"}},
+			verifier: sender.Signing{Secrets: []string{"new"}},
+			body:     []byte(`{"to":"+905551234567","content":"hellO"}`),
+			now:      signedAt,
+			want:     sender.ErrInvalidSignature,
+		},
+		{
+			name:     "replayed",
+			signer:   sender.Signing{Secrets: []string{"new"}},
+			verifier: sender.Signing{Secrets: []string{"new"}},
+			body:     body,
+			now:      signedAt.Add(6 * time.Minute),
+			want:     sender.ErrStaleTimestamp,
+		},
+		{
+			name:     "from the future",
+			signer:   sender.Signing{Secrets: []string{"new"}},
+			verifier: sender.Signing{Secrets: []string{"new"}},
+			body:     body,
+			now:      signedAt.Add(-6 * time.Minute),
+			want:     sender.ErrStaleTimestamp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			tt.signer.Sign(h, body, signedAt)
+
+			err := tt.verifier.Verify(h, tt.body, 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Send_Signed(t *testing.T) {
+	signing := sender.Signing{Secrets: []string{"secret"}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		if err := signing.Verify(r.Header, body, time.Minute, time.Now()); err != nil {
+			t.Errorf("Verify: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message": "Sent", "messageId": "signed"}`))
+	}))
+	defer server.Close()
+
+	client := sender.NewClient(server.URL, "key", sender.WithSigning(signing))
+
+	resp, err := client.Send(context.Background(), "+905551234567", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.MessageID != "signed" {
+		t.Fatalf("MessageID = %q, want signed", resp.MessageID)
+	}
+}
@@ -0,0 +1,190 @@
+package sender_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/infra/sender"
+)
+
+// writePEM writes a PEM block to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// newClientCert creates a self-signed client certificate and returns the
+// paths of the certificate and key files and the certificate itself.
+func newClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "insider"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return writePEM(t, dir, "client.pem", "CERTIFICATE", der), writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER), cert
+}
+
+func okHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(`{"message": "Sent", "messageId": "tls"}`))
+}
+
+func TestTransport_TLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCert := newClientCert(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(okHandler))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MaxVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		name    string
+		cfg     sender.TransportConfig
+		wantErr bool
+	}{
+		{
+			name: "client certificate and ca bundle",
+			cfg:  sender.TransportConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		},
+		{
+			name:    "server not trusted without the ca bundle",
+			cfg:     sender.TransportConfig{CertFile: certFile, KeyFile: keyFile},
+			wantErr: true,
+		},
+		{
+			name:    "rejected without a client certificate",
+			cfg:     sender.TransportConfig{CAFile: caFile},
+			wantErr: true,
+		},
+		{
+			name:    "server below the minimum tls version",
+			cfg:     sender.TransportConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile, MinTLSVersion: "1.3"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := sender.NewTransport(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer transport.CloseIdleConnections()
+
+			client := sender.NewClient(server.URL, "key", sender.WithTransport(transport))
+			resp, err := client.Send(context.Background(), "+905551234567", "hello")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.MessageID != "tls" {
+				t.Fatalf("MessageID = %q, want tls", resp.MessageID)
+			}
+		})
+	}
+}
+
+func TestTransport_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target.
+		proxied = r.URL.String()
+		okHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	transport, err := sender.NewTransport(sender.TransportConfig{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := sender.NewClient("http://provider.invalid/send", "key", sender.WithTransport(transport))
+	if _, err := client.Send(context.Background(), "+905551234567", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://provider.invalid/send" {
+		t.Fatalf("proxy got %q, want the webhook URL", proxied)
+	}
+}
+
+func TestNewTransport_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _, _ := newClientCert(t, dir)
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  sender.TransportConfig
+	}{
+		{name: "certificate without key", cfg: sender.TransportConfig{CertFile: certFile}},
+		{name: "missing ca bundle", cfg: sender.TransportConfig{CAFile: filepath.Join(dir, "missing.pem")}},
+		{name: "empty ca bundle", cfg: sender.TransportConfig{CAFile: notPEM}},
+		{name: "unknown tls version", cfg: sender.TransportConfig{MinTLSVersion: "1.1"}},
+		{name: "relative proxy url", cfg: sender.TransportConfig{ProxyURL: "proxy:3128"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sender.NewTransport(tt.cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
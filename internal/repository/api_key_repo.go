@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+	// List and Revoke only see keys of tenantID unless it is nil.
+	List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.APIKey, error)
+	Revoke(ctx context.Context, id int64, tenantID *int64) (domain.APIKey, error)
+	// Authenticate finds the active key with the given hash and records its
+	// use. It returns ErrNotFound for unknown or revoked keys.
+	Authenticate(ctx context.Context, keyHash string) (domain.APIKey, error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry domain.AuditEntry) (domain.AuditEntry, error)
+	List(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, error)
+}
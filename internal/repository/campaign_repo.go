@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+// CampaignRepository only sees campaigns of tenantID unless it is nil.
+type CampaignRepository interface {
+	Create(ctx context.Context, c domain.Campaign) (domain.Campaign, error)
+	Get(ctx context.Context, id int64, tenantID *int64) (domain.Campaign, error)
+	List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Campaign, error)
+	SetStatus(ctx context.Context, id int64, tenantID *int64, status domain.CampaignStatus) (domain.Campaign, error)
+	Stats(ctx context.Context, id int64) (domain.CampaignStats, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound = errors.New("record not found")
+	ErrConflict = errors.New("record already exists")
+	// ErrInvalidReference is returned when a row references a record, such
+	// as a tenant, that does not exist.
+	ErrInvalidReference = errors.New("referenced record does not exist")
+	ErrQuotaExceeded    = errors.New("quota exceeded")
+)
+
+// isUniqueViolation reports whether err is a Postgres unique constraint error.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// tenantCondition matches rows of the tenant bound to parameter n. A NULL
+// tenant parameter matches every row.
+func tenantCondition(n int) string {
+	return fmt.Sprintf("($%d::bigint IS NULL OR tenant_id = $%d)", n, n)
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key error.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
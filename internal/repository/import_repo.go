@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type ImportRepository interface {
+	Create(ctx context.Context, imp domain.Import) (domain.Import, error)
+	// Get only sees imports of tenantID unless it is nil.
+	Get(ctx context.Context, id int64, tenantID *int64) (domain.Import, error)
+	// Progress stores the counters of a running import and appends the rows
+	// rejected since the last call.
+	Progress(ctx context.Context, id int64, progress domain.ImportProgress) error
+	// Finish moves an import to its final status.
+	Finish(ctx context.Context, id int64, status domain.ImportStatus, reason *string) error
+	// Errors returns the rejected rows of an import in file order.
+	Errors(ctx context.Context, id int64) ([]domain.ImportRowError, error)
+}
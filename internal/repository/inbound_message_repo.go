@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type InboundMessageRepository interface {
+	// Create stores msg and links it to the last outbound message sent to
+	// msg.From. Redelivered messages with a known ExternalID are returned
+	// as stored.
+	Create(ctx context.Context, msg domain.InboundMessage) (domain.InboundMessage, error)
+	// List only returns replies to messages of tenantID unless it is nil.
+	List(ctx context.Context, from string, tenantID *int64, limit, offset int) ([]domain.InboundMessage, error)
+}
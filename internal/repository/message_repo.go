@@ -7,9 +7,63 @@ import (
 	"github.com/LevanPro/insider/internal/domain"
 )
 
+// ContentCipher seals message content for storage with envelope
+// encryption: keyID names the key encryption key that wrapped the data key
+// dek. It is implemented by keyring.Keyring.
+type ContentCipher interface {
+	ActiveKeyID() string
+	Seal(plaintext []byte) (ciphertext []byte, keyID string, dek []byte, err error)
+	Open(ciphertext []byte, keyID string, dek []byte) ([]byte, error)
+	// Rewrap wraps dek with the active key.
+	Rewrap(keyID string, dek []byte) (string, []byte, error)
+	// Hash is a keyed hash of data, stored as the content hash of sealed
+	// content in place of a plain hash that would give the content away.
+	Hash(data []byte) []byte
+}
+
 type MessageRepository interface {
+	// GetNextUnsent claims up to limit pending messages by moving them to
+	// processing, so concurrent runs never pick the same message. Tenants
+	// take turns, so one tenant's backlog cannot starve the others.
 	GetNextUnsent(ctx context.Context, limit int) ([]domain.Message, error)
-	MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string) error
-	MarkAsFailed(ctx context.Context, id int64) error
-	ListSent(ctx context.Context, limit, offset int) ([]domain.Message, error)
+	Create(ctx context.Context, msg domain.Message) (domain.Message, error)
+	// Get, ListSent and Cancel only see messages of tenantID unless it is
+	// nil; the filter based methods are scoped by MessageFilter.TenantID.
+	Get(ctx context.Context, id int64, tenantID *int64) (domain.Message, error)
+	// MarkAsSent records the send and what it cost.
+	MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string, cost domain.MessageCost) error
+	MarkAsFailed(ctx context.Context, id int64, reason string) error
+	MarkAsSuppressed(ctx context.Context, id int64, reason string) error
+	// Defer returns a claimed message to pending, not to be picked up
+	// before notBefore.
+	Defer(ctx context.Context, id int64, notBefore time.Time, reason string) error
+	// ReserveSend reserves a send of message id to a recipient unless
+	// maxMessages were sent or reserved for it since the given time. It returns
+	// that count and the time of the oldest of them. Reservations of one
+	// recipient are serialized.
+	ReserveSend(ctx context.Context, id int64, to string, since time.Time, maxMessages int) (bool, int, time.Time, error)
+	// FindDuplicate returns the ID of an earlier message with the same
+	// recipient, content hash and tenant that was created or sent within
+	// window.
+	// It returns ErrNotFound when there is none.
+	FindDuplicate(ctx context.Context, msg domain.Message, window time.Duration) (int64, error)
+	MarkAsDuplicate(ctx context.Context, id, duplicateOf int64) error
+	ListSent(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Message, error)
+	List(ctx context.Context, filter domain.MessageFilter, limit, offset int) ([]domain.Message, error)
+	// Export calls fn for every message matching filter in ID order, reading
+	// the rows as they arrive rather than loading them all. It stops at the
+	// first error of fn and returns it.
+	Export(ctx context.Context, filter domain.MessageFilter, fn func(domain.Message) error) error
+	// Requeue moves failed messages back to pending, resets their attempt
+	// counter and records each requeue. It returns the requeued message IDs.
+	Requeue(ctx context.Context, ids []int64, filter *domain.MessageFilter, requeuedBy string, reason *string) ([]int64, error)
+	// Cancel moves a pending message to cancelled. It returns ErrNotFound when
+	// no pending message with that ID exists.
+	Cancel(ctx context.Context, id int64, tenantID *int64) (domain.Message, error)
+	// CancelMatching cancels every pending message matching the selection.
+	CancelMatching(ctx context.Context, ids []int64, filter domain.MessageFilter) ([]int64, error)
+	// EncryptBatch seals up to limit stored messages, archived ones
+	// included, that are in plaintext or sealed under a key other than the
+	// active one. It returns how many it changed.
+	EncryptBatch(ctx context.Context, limit int) (int, error)
 }
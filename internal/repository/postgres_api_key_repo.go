@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, created_by, created_at, last_used_at, revoked_at, tenant_id, daily_quota, monthly_quota`
+
+type apiKeyRow struct {
+	ID         int64          `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	CreatedBy  string         `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+	TenantID   *int64         `db:"tenant_id"`
+	domain.Quota
+}
+
+func (r apiKeyRow) toDomain() domain.APIKey {
+	return domain.APIKey{
+		ID:         r.ID,
+		Name:       r.Name,
+		Prefix:     r.Prefix,
+		KeyHash:    r.KeyHash,
+		Scopes:     []string(r.Scopes),
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+		LastUsedAt: r.LastUsedAt,
+		RevokedAt:  r.RevokedAt,
+		TenantID:   r.TenantID,
+		Quota:      r.Quota,
+	}
+}
+
+type PostgresAPIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresAPIKeyRepository(db *sqlx.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	var row apiKeyRow
+	err := r.db.GetContext(ctx, &row, `
+      INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, tenant_id, daily_quota, monthly_quota)
+      VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+      RETURNING `+apiKeyColumns,
+		key.Name, key.Prefix, key.KeyHash, pq.StringArray(key.Scopes), key.CreatedBy, key.TenantID, key.Daily, key.Monthly)
+	if isForeignKeyViolation(err) {
+		return domain.APIKey{}, ErrInvalidReference
+	}
+	return row.toDomain(), err
+}
+
+func (r *PostgresAPIKeyRepository) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.APIKey, error) {
+	var rows []apiKeyRow
+	err := r.db.SelectContext(ctx, &rows, `
+      SELECT `+apiKeyColumns+`
+      FROM api_keys
+      WHERE `+tenantCondition(3)+`
+      ORDER BY id DESC
+      LIMIT $1 OFFSET $2
+    `, limit, offset, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.toDomain())
+	}
+
+	return keys, nil
+}
+
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id int64, tenantID *int64) (domain.APIKey, error) {
+	var row apiKeyRow
+	err := r.db.GetContext(ctx, &row, `
+      UPDATE api_keys
+      SET revoked_at = COALESCE(revoked_at, NOW())
+      WHERE id = $1 AND `+tenantCondition(2)+`
+      RETURNING `+apiKeyColumns, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, ErrNotFound
+	}
+	return row.toDomain(), err
+}
+
+func (r *PostgresAPIKeyRepository) Authenticate(ctx context.Context, keyHash string) (domain.APIKey, error) {
+	var row apiKeyRow
+	err := r.db.GetContext(ctx, &row, `
+      UPDATE api_keys
+      SET last_used_at = NOW()
+      WHERE key_hash = $1 AND revoked_at IS NULL
+      RETURNING `+apiKeyColumns, keyHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.APIKey{}, ErrNotFound
+	}
+	return row.toDomain(), err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const auditColumns = `id, actor, action, target, params::text AS params, host(source_ip) AS source_ip, created_at`
+
+type auditRow struct {
+	ID        int64          `db:"id"`
+	Actor     string         `db:"actor"`
+	Action    string         `db:"action"`
+	Target    string         `db:"target"`
+	Params    sql.NullString `db:"params"`
+	SourceIP  sql.NullString `db:"source_ip"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func (r auditRow) toDomain() domain.AuditEntry {
+	entry := domain.AuditEntry{
+		ID:        r.ID,
+		Actor:     r.Actor,
+		Action:    r.Action,
+		Target:    r.Target,
+		SourceIP:  r.SourceIP.String,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.Params.Valid {
+		entry.Params = json.RawMessage(r.Params.String)
+	}
+	return entry
+}
+
+type PostgresAuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresAuditRepository(db *sqlx.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+func (r *PostgresAuditRepository) Create(ctx context.Context, entry domain.AuditEntry) (domain.AuditEntry, error) {
+	var params *string
+	if len(entry.Params) > 0 {
+		s := string(entry.Params)
+		params = &s
+	}
+
+	var row auditRow
+	err := r.db.GetContext(ctx, &row, `
+      INSERT INTO audit_log (actor, action, target, params, source_ip)
+      VALUES ($1, $2, $3, $4::jsonb, NULLIF($5, '')::inet)
+      RETURNING `+auditColumns,
+		entry.Actor, entry.Action, entry.Target, params, entry.SourceIP)
+	return row.toDomain(), err
+}
+
+func (r *PostgresAuditRepository) List(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, error) {
+	args := []any{limit, offset}
+	conditions := []string{"TRUE"}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.Target != "" {
+		add("target = $%d", filter.Target)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedUntil != nil {
+		add("created_at < $%d", *filter.CreatedUntil)
+	}
+
+	var rows []auditRow
+	err := r.db.SelectContext(ctx, &rows, fmt.Sprintf(`
+      SELECT %s
+      FROM audit_log
+      WHERE %s
+      ORDER BY created_at DESC, id DESC
+      LIMIT $1 OFFSET $2
+    `, auditColumns, strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, row.toDomain())
+	}
+	return entries, nil
+}
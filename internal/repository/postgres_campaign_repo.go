@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const campaignColumns = `id, name, status, dedup_window_seconds, tenant_id, created_at, updated_at`
+
+type PostgresCampaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresCampaignRepository(db *sqlx.DB) *PostgresCampaignRepository {
+	return &PostgresCampaignRepository{db: db}
+}
+
+func (r *PostgresCampaignRepository) Create(ctx context.Context, c domain.Campaign) (domain.Campaign, error) {
+	var created domain.Campaign
+	err := r.db.GetContext(ctx, &created, `
+      INSERT INTO campaigns (name, dedup_window_seconds, tenant_id)
+      VALUES ($1, $2, $3)
+      RETURNING `+campaignColumns, c.Name, c.DedupWindowSeconds, c.TenantID)
+	if isForeignKeyViolation(err) {
+		return created, ErrInvalidReference
+	}
+	return created, err
+}
+
+func (r *PostgresCampaignRepository) Get(ctx context.Context, id int64, tenantID *int64) (domain.Campaign, error) {
+	var c domain.Campaign
+	err := r.db.GetContext(ctx, &c, `
+      SELECT `+campaignColumns+`
+      FROM campaigns
+      WHERE id = $1 AND `+tenantCondition(2)+`
+    `, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrNotFound
+	}
+	return c, err
+}
+
+func (r *PostgresCampaignRepository) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Campaign, error) {
+	var campaigns []domain.Campaign
+	err := r.db.SelectContext(ctx, &campaigns, `
+      SELECT `+campaignColumns+`
+      FROM campaigns
+      WHERE `+tenantCondition(3)+`
+      ORDER BY id DESC
+      LIMIT $1 OFFSET $2
+    `, limit, offset, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+func (r *PostgresCampaignRepository) SetStatus(ctx context.Context, id int64, tenantID *int64, status domain.CampaignStatus) (domain.Campaign, error) {
+	var c domain.Campaign
+	err := r.db.GetContext(ctx, &c, `
+      UPDATE campaigns
+      SET status = $2,
+          updated_at = NOW()
+      WHERE id = $1 AND `+tenantCondition(3)+`
+      RETURNING `+campaignColumns, id, status, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, ErrNotFound
+	}
+	return c, err
+}
+
+func (r *PostgresCampaignRepository) Stats(ctx context.Context, id int64) (domain.CampaignStats, error) {
+	var stats domain.CampaignStats
+	err := r.db.GetContext(ctx, &stats, `
+      SELECT COUNT(*) AS total,
+             COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+             COUNT(*) FILTER (WHERE status = 'processing') AS processing,
+             COUNT(*) FILTER (WHERE status = 'sent') AS sent,
+             COUNT(*) FILTER (WHERE status = 'delivered') AS delivered,
+             COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+             COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+             COUNT(*) FILTER (WHERE status = 'suppressed') AS suppressed,
+             COUNT(*) FILTER (WHERE status = 'duplicate') AS duplicate
+      FROM messages
+      WHERE campaign_id = $1
+    `, id)
+	return stats, err
+}
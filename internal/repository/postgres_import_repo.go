@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const importColumns = `id, status, filename, columns, template_id, campaign_id, tenant_id, created_by, total_rows, processed_rows, accepted_rows, rejected_rows, error, created_at, updated_at, completed_at`
+
+type importRow struct {
+	ID            int64               `db:"id"`
+	Status        domain.ImportStatus `db:"status"`
+	Filename      string              `db:"filename"`
+	Columns       pq.StringArray      `db:"columns"`
+	TemplateID    *int64              `db:"template_id"`
+	CampaignID    *int64              `db:"campaign_id"`
+	TenantID      *int64              `db:"tenant_id"`
+	CreatedBy     string              `db:"created_by"`
+	TotalRows     int                 `db:"total_rows"`
+	ProcessedRows int                 `db:"processed_rows"`
+	AcceptedRows  int                 `db:"accepted_rows"`
+	RejectedRows  int                 `db:"rejected_rows"`
+	Error         *string             `db:"error"`
+	CreatedAt     time.Time           `db:"created_at"`
+	UpdatedAt     time.Time           `db:"updated_at"`
+	CompletedAt   *time.Time          `db:"completed_at"`
+}
+
+func (r importRow) toDomain() domain.Import {
+	return domain.Import{
+		ID:            r.ID,
+		Status:        r.Status,
+		Filename:      r.Filename,
+		Columns:       []string(r.Columns),
+		TemplateID:    r.TemplateID,
+		CampaignID:    r.CampaignID,
+		TenantID:      r.TenantID,
+		CreatedBy:     r.CreatedBy,
+		TotalRows:     r.TotalRows,
+		ProcessedRows: r.ProcessedRows,
+		AcceptedRows:  r.AcceptedRows,
+		RejectedRows:  r.RejectedRows,
+		Error:         r.Error,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+		CompletedAt:   r.CompletedAt,
+	}
+}
+
+type PostgresImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresImportRepository(db *sqlx.DB) *PostgresImportRepository {
+	return &PostgresImportRepository{db: db}
+}
+
+func (r *PostgresImportRepository) Create(ctx context.Context, imp domain.Import) (domain.Import, error) {
+	var row importRow
+	err := r.db.GetContext(ctx, &row, `
+      INSERT INTO imports (filename, columns, template_id, campaign_id, tenant_id, created_by, total_rows)
+      VALUES ($1, $2, $3, $4, $5, $6, $7)
+      RETURNING `+importColumns,
+		imp.Filename, pq.StringArray(imp.Columns), imp.TemplateID, imp.CampaignID, imp.TenantID, imp.CreatedBy, imp.TotalRows)
+	if isForeignKeyViolation(err) {
+		return domain.Import{}, ErrInvalidReference
+	}
+	return row.toDomain(), err
+}
+
+func (r *PostgresImportRepository) Get(ctx context.Context, id int64, tenantID *int64) (domain.Import, error) {
+	var row importRow
+	err := r.db.GetContext(ctx, &row, `
+      SELECT `+importColumns+`
+      FROM imports
+      WHERE id = $1 AND `+tenantCondition(2)+`
+    `, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Import{}, ErrNotFound
+	}
+	return row.toDomain(), err
+}
+
+func (r *PostgresImportRepository) Progress(ctx context.Context, id int64, progress domain.ImportProgress) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, e := range progress.Errors {
+		if _, err := tx.ExecContext(ctx, `
+          INSERT INTO import_errors (import_id, line, record, reason)
+          VALUES ($1, $2, $3, $4)
+        `, id, e.Line, pq.StringArray(e.Record), e.Reason); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+      UPDATE imports
+      SET processed_rows = $2,
+          accepted_rows = $3,
+          rejected_rows = $4,
+          updated_at = NOW()
+      WHERE id = $1
+    `, id, progress.Processed, progress.Accepted, progress.Rejected); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresImportRepository) Finish(ctx context.Context, id int64, status domain.ImportStatus, reason *string) error {
+	_, err := r.db.ExecContext(ctx, `
+      UPDATE imports
+      SET status = $2,
+          error = $3,
+          completed_at = NOW(),
+          updated_at = NOW()
+      WHERE id = $1
+    `, id, status, reason)
+	return err
+}
+
+func (r *PostgresImportRepository) Errors(ctx context.Context, id int64) ([]domain.ImportRowError, error) {
+	var rows []struct {
+		Line   int            `db:"line"`
+		Record pq.StringArray `db:"record"`
+		Reason string         `db:"reason"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+      SELECT line, record, reason
+      FROM import_errors
+      WHERE import_id = $1
+      ORDER BY line
+    `, id)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]domain.ImportRowError, 0, len(rows))
+	for _, row := range rows {
+		errs = append(errs, domain.ImportRowError{Line: row.Line, Record: []string(row.Record), Reason: row.Reason})
+	}
+	return errs, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const inboundMessageColumns = `id, "from", "to", content, external_id, message_id, opt_out, received_at, created_at`
+
+type PostgresInboundMessageRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresInboundMessageRepository(db *sqlx.DB) *PostgresInboundMessageRepository {
+	return &PostgresInboundMessageRepository{db: db}
+}
+
+func (r *PostgresInboundMessageRepository) Create(ctx context.Context, msg domain.InboundMessage) (domain.InboundMessage, error) {
+	var created domain.InboundMessage
+	err := r.db.GetContext(ctx, &created, `
+      INSERT INTO inbound_messages ("from", "to", content, external_id, message_id, opt_out, received_at)
+      VALUES (
+          $1, $2, $3, $4,
+          (
+              SELECT id
+              FROM messages
+              WHERE "to" = $1 AND sent_at IS NOT NULL
+              ORDER BY sent_at DESC
+              LIMIT 1
+          ),
+          $5, $6
+      )
+      ON CONFLICT (external_id) DO UPDATE SET external_id = EXCLUDED.external_id
+      RETURNING `+inboundMessageColumns,
+		msg.From, msg.To, msg.Content, msg.ExternalID, msg.OptOut, msg.ReceivedAt)
+	return created, err
+}
+
+func (r *PostgresInboundMessageRepository) List(ctx context.Context, from string, tenantID *int64, limit, offset int) ([]domain.InboundMessage, error) {
+	args := []any{limit, offset}
+	where := "TRUE"
+
+	if from != "" {
+		args = append(args, from)
+		where = fmt.Sprintf(`"from" = $%d`, len(args))
+	}
+	if tenantID != nil {
+		args = append(args, *tenantID)
+		where += fmt.Sprintf(` AND message_id IN (SELECT id FROM messages WHERE tenant_id = $%d)`, len(args))
+	}
+
+	var msgs []domain.InboundMessage
+	err := r.db.SelectContext(ctx, &msgs, fmt.Sprintf(`
+      SELECT %s
+      FROM inbound_messages
+      WHERE %s
+      ORDER BY received_at DESC, id DESC
+      LIMIT $1 OFFSET $2
+    `, inboundMessageColumns, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
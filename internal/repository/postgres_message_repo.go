@@ -2,73 +2,684 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/LevanPro/insider/internal/domain"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// staleProcessingAfter is how long a claimed message may stay in processing
+// before it is considered abandoned, e.g. after a crash, and claimed again.
+const staleProcessingAfter = 10 * time.Minute
+
+// frequencyCapLockClass is the first key of the advisory locks taken per
+// recipient by ReserveSend.
+const frequencyCapLockClass = 7234002
+
+const messageColumns = `id, "to", content, status, sent_at, external_id, attempts, last_error, campaign_id, template_id, not_before, content_hash, dedup_window_seconds, duplicate_of, tenant_id, provider, segments, unit_price, key_id, dek, created_at, updated_at`
+
+// notPausedCampaign excludes messages of paused campaigns.
+const notPausedCampaign = `NOT EXISTS (
+                          SELECT 1
+                          FROM campaigns c
+                          WHERE c.id = campaign_id AND c.status = 'paused'
+                      )`
+
+// messageRow is a stored message with the envelope of its content. A nil
+// KeyID means the content is stored in plaintext.
+type messageRow struct {
+	domain.Message
+	KeyID *string `db:"key_id"`
+	DEK   []byte  `db:"dek"`
+}
+
 type PostgresMessageRepository struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	cipher ContentCipher
 }
 
-func NewPostgresMessageRepository(db *sqlx.DB) *PostgresMessageRepository {
-	return &PostgresMessageRepository{db: db}
+// NewPostgresMessageRepository stores content sealed by cipher, or in
+// plaintext when cipher is nil.
+func NewPostgresMessageRepository(db *sqlx.DB, cipher ContentCipher) *PostgresMessageRepository {
+	return &PostgresMessageRepository{db: db, cipher: cipher}
 }
 
 func (r *PostgresMessageRepository) GetNextUnsent(ctx context.Context, limit int) ([]domain.Message, error) {
-	var msgs []domain.Message
-	err := r.db.SelectContext(ctx, &msgs, `
-      SELECT id, "to", content, status, sent_at, external_id, created_at, updated_at
+	// Candidates are bounded to limit per tenant, read from the pending index
+	// of each tenant, so ranking them never touches the whole backlog.
+	var rows []messageRow
+	err := r.db.SelectContext(ctx, &rows, `
+      UPDATE messages
+      SET status = 'processing',
+          updated_at = NOW()
+      FROM (
+          SELECT m.id AS claim_id
+          FROM messages m
+          JOIN (
+              SELECT id, ROW_NUMBER() OVER (PARTITION BY tenant_id ORDER BY id) AS turn
+              FROM (
+                  SELECT p.id, p.tenant_id
+                  FROM tenants t
+                  CROSS JOIN LATERAL (
+                      SELECT id, tenant_id
+                      FROM messages
+                      WHERE tenant_id = t.id
+                        AND status = 'pending'
+                        AND (not_before IS NULL OR not_before <= NOW())
+                        AND `+notPausedCampaign+`
+                      ORDER BY id
+                      LIMIT $1
+                  ) p
+                  UNION ALL
+                  (
+                      SELECT id, tenant_id
+                      FROM messages
+                      WHERE tenant_id IS NULL
+                        AND status = 'pending'
+                        AND (not_before IS NULL OR not_before <= NOW())
+                        AND `+notPausedCampaign+`
+                      ORDER BY id
+                      LIMIT $1
+                  )
+                  UNION ALL
+                  (
+                      SELECT id, tenant_id
+                      FROM messages
+                      WHERE status = 'processing'
+                        AND updated_at < NOW() - $2 * INTERVAL '1 second'
+                        AND `+notPausedCampaign+`
+                      ORDER BY id
+                      LIMIT $1
+                  )
+              ) candidates
+          ) ranked ON ranked.id = m.id
+          -- Re-checked after locking in case a concurrent run changed the row.
+          WHERE ((m.status = 'pending' AND (m.not_before IS NULL OR m.not_before <= NOW()))
+                 OR (m.status = 'processing' AND m.updated_at < NOW() - $2 * INTERVAL '1 second'))
+          ORDER BY ranked.turn, m.id
+          LIMIT $1
+          FOR UPDATE OF m SKIP LOCKED
+      ) claimed
+      WHERE id = claimed.claim_id
+      RETURNING `+messageColumns+`
+    `, limit, staleProcessingAfter.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := r.openAll(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+
+	return msgs, nil
+}
+
+func (r *PostgresMessageRepository) Create(ctx context.Context, msg domain.Message) (domain.Message, error) {
+	content, keyID, dek, err := r.seal(msg.Content)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if msg.ContentHash != nil {
+		hash := r.contentHash(msg.Content, *msg.ContentHash)
+		msg.ContentHash = &hash
+	}
+
+	var row messageRow
+	err = r.db.GetContext(ctx, &row, `
+      INSERT INTO messages ("to", content, key_id, dek, campaign_id, template_id, content_hash, dedup_window_seconds, tenant_id)
+      VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+      RETURNING `+messageColumns,
+		msg.To, content, keyID, dek, msg.CampaignID, msg.TemplateID, msg.ContentHash, msg.DedupWindowSeconds, msg.TenantID)
+	if isForeignKeyViolation(err) {
+		return domain.Message{}, ErrInvalidReference
+	}
+	if err != nil {
+		return domain.Message{}, err
+	}
+
+	created := row.Message
+	created.Content = msg.Content
+	return created, nil
+}
+
+func (r *PostgresMessageRepository) Get(ctx context.Context, id int64, tenantID *int64) (domain.Message, error) {
+	var row messageRow
+	err := r.db.GetContext(ctx, &row, `
+      SELECT `+messageColumns+`
       FROM messages
-      WHERE status = 'pending'
-      ORDER BY id
-      LIMIT $1
-    `, limit)
-	return msgs, err
+      WHERE id = $1 AND `+tenantCondition(2)+`
+    `, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Message{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Message{}, err
+	}
+	return r.open(row)
 }
 
-func (r *PostgresMessageRepository) MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string) error {
+func (r *PostgresMessageRepository) MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string, cost domain.MessageCost) error {
 	_, err := r.db.ExecContext(ctx, `
       UPDATE messages
       SET status = 'sent',
           sent_at = $2,
           external_id = $3,
+          provider = $4,
+          segments = $5,
+          unit_price = $6,
+          attempts = attempts + 1,
+          last_error = NULL,
           updated_at = NOW()
       WHERE id = $1
-    `, id, sentAt, externalID)
+    `, id, sentAt, externalID, cost.Provider, cost.Segments, cost.UnitPrice)
 	return err
 }
 
-func (r *PostgresMessageRepository) MarkAsFailed(ctx context.Context, id int64) error {
+func (r *PostgresMessageRepository) MarkAsFailed(ctx context.Context, id int64, reason string) error {
 	_, err := r.db.ExecContext(ctx, `
       UPDATE messages
       SET status = 'failed',
+          attempts = attempts + 1,
+          last_error = $2,
+          updated_at = NOW()
+      WHERE id = $1
+    `, id, reason)
+	return err
+}
+
+func (r *PostgresMessageRepository) MarkAsSuppressed(ctx context.Context, id int64, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+      UPDATE messages
+      SET status = 'suppressed',
+          last_error = $2,
+          updated_at = NOW()
+      WHERE id = $1
+    `, id, reason)
+	return err
+}
+
+func (r *PostgresMessageRepository) Defer(ctx context.Context, id int64, notBefore time.Time, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+      UPDATE messages
+      SET status = 'pending',
+          not_before = $2,
+          last_error = $3,
           updated_at = NOW()
       WHERE id = $1
-    `, id)
+    `, id, notBefore, reason)
+	return err
+}
+
+func (r *PostgresMessageRepository) ReserveSend(ctx context.Context, id int64, to string, since time.Time, maxMessages int) (bool, int, time.Time, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	// The two key form keeps recipient locks apart from the leader lock.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, frequencyCapLockClass, to); err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	// Messages still in processing count with their reservation, so a
+	// failed send gives its place back.
+	var row struct {
+		Count  int        `db:"count"`
+		Oldest *time.Time `db:"oldest"`
+	}
+	err = tx.GetContext(ctx, &row, `
+      SELECT COUNT(*) AS count, MIN(COALESCE(sent_at, cap_reserved_at)) AS oldest
+      FROM messages
+      WHERE "to" = $1
+        AND id <> $2
+        AND (sent_at >= $3 OR (status = 'processing' AND cap_reserved_at >= $3))
+    `, to, id, since)
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	var oldest time.Time
+	if row.Oldest != nil {
+		oldest = *row.Oldest
+	}
+
+	if row.Count >= maxMessages {
+		return false, row.Count, oldest, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+      UPDATE messages
+      SET cap_reserved_at = NOW()
+      WHERE id = $1
+    `, id); err != nil {
+		return false, 0, time.Time{}, err
+	}
+
+	return true, row.Count, oldest, tx.Commit()
+}
+
+func (r *PostgresMessageRepository) FindDuplicate(ctx context.Context, msg domain.Message, window time.Duration) (int64, error) {
+	var id int64
+	err := r.db.GetContext(ctx, &id, `
+      SELECT id
+      FROM messages
+      WHERE "to" = $1
+        AND content_hash = $2
+        AND id < $3
+        AND tenant_id IS NOT DISTINCT FROM $6
+        AND status IN ('pending', 'processing', 'sent', 'delivered')
+        AND (created_at >= $4::timestamptz - $5 * INTERVAL '1 second'
+             OR sent_at >= NOW() - $5 * INTERVAL '1 second')
+      ORDER BY id
+      LIMIT 1
+    `, msg.To, msg.ContentHash, msg.ID, msg.CreatedAt, window.Seconds(), msg.TenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	return id, err
+}
+
+func (r *PostgresMessageRepository) MarkAsDuplicate(ctx context.Context, id, duplicateOf int64) error {
+	_, err := r.db.ExecContext(ctx, `
+      UPDATE messages
+      SET status = 'duplicate',
+          duplicate_of = $2,
+          last_error = 'duplicate of message ' || $2::text,
+          updated_at = NOW()
+      WHERE id = $1
+    `, id, duplicateOf)
 	return err
 }
 
 func (r *PostgresMessageRepository) ListSent(
 	ctx context.Context,
+	tenantID *int64,
 	limit, offset int,
 ) ([]domain.Message, error) {
 
-	var msgs []domain.Message
+	var rows []messageRow
 
 	query := `
-        SELECT id, "to", content, status, sent_at, external_id, created_at, updated_at
+        SELECT ` + messageColumns + `
         FROM messages
-        WHERE status = 'sent'
+        WHERE status = 'sent' AND ` + tenantCondition(3) + `
         ORDER BY sent_at DESC
         LIMIT $1 OFFSET $2
     `
 
-	err := r.db.SelectContext(ctx, &msgs, query, limit, offset)
+	err := r.db.SelectContext(ctx, &rows, query, limit, offset, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.openAll(rows)
+}
+
+func (r *PostgresMessageRepository) List(
+	ctx context.Context,
+	filter domain.MessageFilter,
+	limit, offset int,
+) ([]domain.Message, error) {
+
+	var rows []messageRow
+
+	where, args := messageFilterClause(filter, nil)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM messages
+        WHERE %s
+        ORDER BY updated_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, messageColumns, where, len(args)-1, len(args))
+
+	err := r.db.SelectContext(ctx, &rows, query, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	return r.openAll(rows)
+}
+
+func (r *PostgresMessageRepository) Export(
+	ctx context.Context,
+	filter domain.MessageFilter,
+	fn func(domain.Message) error,
+) error {
+
+	where, args := messageFilterClause(filter, nil)
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM messages
+        WHERE %s
+        ORDER BY id
+    `, messageColumns, where)
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row messageRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+		msg, err := r.open(row)
+		if err != nil {
+			return err
+		}
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *PostgresMessageRepository) Requeue(
+	ctx context.Context,
+	ids []int64,
+	filter *domain.MessageFilter,
+	requeuedBy string,
+	reason *string,
+) ([]int64, error) {
+
+	args := []any{requeuedBy, reason}
+	conditions := []string{"status = 'failed'"}
+
+	if len(ids) > 0 {
+		args = append(args, pq.Array(ids))
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d)", len(args)))
+	}
+
+	if filter != nil {
+		var where string
+		where, args = messageFilterClause(*filter, args)
+		conditions = append(conditions, where)
+	}
+
+	query := fmt.Sprintf(`
+        WITH candidates AS (
+            SELECT id, attempts, last_error
+            FROM messages
+            WHERE %s
+            FOR UPDATE
+        ), moved AS (
+            UPDATE messages m
+            SET status = 'pending',
+                attempts = 0,
+                last_error = NULL,
+                not_before = NULL,
+                updated_at = NOW()
+            FROM candidates c
+            WHERE m.id = c.id
+            RETURNING c.id, c.attempts, c.last_error
+        )
+        INSERT INTO message_requeues (message_id, previous_attempts, previous_error, requeued_by, reason)
+        SELECT id, attempts, last_error, $1, $2
+        FROM moved
+        RETURNING message_id
+    `, strings.Join(conditions, " AND "))
+
+	var requeued []int64
+	if err := r.db.SelectContext(ctx, &requeued, query, args...); err != nil {
+		return nil, err
+	}
+
+	return requeued, nil
+}
+
+func (r *PostgresMessageRepository) Cancel(ctx context.Context, id int64, tenantID *int64) (domain.Message, error) {
+	var row messageRow
+	err := r.db.GetContext(ctx, &row, `
+      UPDATE messages
+      SET status = 'cancelled',
+          updated_at = NOW()
+      WHERE id = $1 AND status = 'pending' AND `+tenantCondition(2)+`
+      RETURNING `+messageColumns+`
+    `, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Message{}, ErrNotFound
+	}
+	if err != nil {
+		return domain.Message{}, err
+	}
+	return r.open(row)
+}
+
+func (r *PostgresMessageRepository) CancelMatching(
+	ctx context.Context,
+	ids []int64,
+	filter domain.MessageFilter,
+) ([]int64, error) {
+
+	filter.Status = domain.StatusPending
+	where, args := messageFilterClause(filter, nil)
+
+	if len(ids) > 0 {
+		args = append(args, pq.Array(ids))
+		where += fmt.Sprintf(" AND id = ANY($%d)", len(args))
+	}
+
+	query := fmt.Sprintf(`
+        UPDATE messages
+        SET status = 'cancelled',
+            updated_at = NOW()
+        WHERE %s
+        RETURNING id
+    `, where)
+
+	var cancelled []int64
+	if err := r.db.SelectContext(ctx, &cancelled, query, args...); err != nil {
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+// EncryptBatch seals plaintext content and rewraps data keys of retired key
+// encryption keys, in messages first and then in messages_archive.
+// updated_at is left alone, so retention still sees when a message finished.
+func (r *PostgresMessageRepository) EncryptBatch(ctx context.Context, limit int) (int, error) {
+	if r.cipher == nil {
+		return 0, errors.New("message encryption is not configured")
+	}
+
+	encrypted := 0
+	for _, table := range []string{"messages", "messages_archive"} {
+		n, err := r.encryptTable(ctx, table, limit-encrypted)
+		encrypted += n
+		if err != nil || encrypted >= limit {
+			return encrypted, err
+		}
+	}
+	return encrypted, nil
+}
+
+func (r *PostgresMessageRepository) encryptTable(ctx context.Context, table string, limit int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var rows []struct {
+		ID          int64   `db:"id"`
+		Content     string  `db:"content"`
+		KeyID       *string `db:"key_id"`
+		DEK         []byte  `db:"dek"`
+		ContentHash *string `db:"content_hash"`
+	}
+	err = tx.SelectContext(ctx, &rows, `
+        SELECT id, content, key_id, dek, content_hash
+        FROM `+table+`
+        WHERE key_id IS DISTINCT FROM $1
+        ORDER BY id
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    `, r.cipher.ActiveKeyID(), limit)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int64, len(rows))
+	contents := make([]string, len(rows))
+	keyIDs := make([]string, len(rows))
+	deks := make([][]byte, len(rows))
+	// An empty hash stands for NULL, for rows stored without one.
+	hashes := make([]string, len(rows))
+
+	for i, row := range rows {
+		ids[i] = row.ID
+		if row.KeyID == nil {
+			content, keyID, dek, err := r.seal(row.Content)
+			if err != nil {
+				return 0, err
+			}
+			contents[i], keyIDs[i], deks[i] = content, *keyID, dek
+			if row.ContentHash != nil {
+				hashes[i] = r.contentHash(row.Content, *row.ContentHash)
+			}
+			continue
+		}
+
+		// Only the data key changes; the content stays as sealed. The hash
+		// follows the active key, so it is computed again.
+		keyID, dek, err := r.cipher.Rewrap(*row.KeyID, row.DEK)
+		if err != nil {
+			return 0, fmt.Errorf("rewrap %s %d: %w", table, row.ID, err)
+		}
+		contents[i], keyIDs[i], deks[i] = row.Content, keyID, dek
+		if row.ContentHash != nil {
+			msg, err := r.open(messageRow{Message: domain.Message{ID: row.ID, Content: row.Content}, KeyID: row.KeyID, DEK: row.DEK})
+			if err != nil {
+				return 0, err
+			}
+			hashes[i] = r.contentHash(msg.Content, *row.ContentHash)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE `+table+` t
+        SET content = u.content,
+            key_id = u.key_id,
+            dek = u.dek,
+            content_hash = NULLIF(u.content_hash, '')
+        FROM unnest($1::bigint[], $2::text[], $3::text[], $4::bytea[], $5::text[]) AS u(id, content, key_id, dek, content_hash)
+        WHERE t.id = u.id
+    `, pq.Array(ids), pq.Array(contents), pq.Array(keyIDs), pq.Array(deks), pq.Array(hashes))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rows), tx.Commit()
+}
+
+// contentHash returns the stored dedup hash of content: a hash keyed by the
+// cipher, or plain, the hash computed by the caller, without one. A plain
+// hash of sealed content would let anyone reading the table guess short
+// content such as one time codes. Duplicates are found by comparing stored
+// hashes, so every stored hash of sealed content is keyed the same way.
+func (r *PostgresMessageRepository) contentHash(content, plain string) string {
+	if r.cipher == nil {
+		return plain
+	}
+	return hex.EncodeToString(r.cipher.Hash([]byte(content)))
+}
+
+// seal returns the stored form of content: base64 ciphertext with its key
+// ID and wrapped data key, or content itself without a cipher.
+func (r *PostgresMessageRepository) seal(content string) (string, *string, []byte, error) {
+	if r.cipher == nil {
+		return content, nil, nil, nil
+	}
+
+	ciphertext, keyID, dek, err := r.cipher.Seal([]byte(content))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("seal content: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), &keyID, dek, nil
+}
+
+// open returns the message of row with its content decrypted.
+func (r *PostgresMessageRepository) open(row messageRow) (domain.Message, error) {
+	msg := row.Message
+	if row.KeyID == nil {
+		return msg, nil
+	}
+	if r.cipher == nil {
+		return domain.Message{}, fmt.Errorf("message %d is encrypted but message encryption is not configured", msg.ID)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(msg.Content)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("decode content of message %d: %w", msg.ID, err)
+	}
+	content, err := r.cipher.Open(ciphertext, *row.KeyID, row.DEK)
+	if err != nil {
+		return domain.Message{}, fmt.Errorf("open content of message %d: %w", msg.ID, err)
+	}
+
+	msg.Content = string(content)
+	return msg, nil
+}
+
+func (r *PostgresMessageRepository) openAll(rows []messageRow) ([]domain.Message, error) {
+	msgs := make([]domain.Message, 0, len(rows))
+	for _, row := range rows {
+		msg, err := r.open(row)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
 	return msgs, nil
 }
+
+// messageFilterClause renders filter as a WHERE clause, appending its
+// parameters to args.
+func messageFilterClause(filter domain.MessageFilter, args []any) (string, []any) {
+	conditions := []string{"TRUE"}
+
+	add := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.To != "" {
+		add(`"to" = $%d`, filter.To)
+	}
+	if filter.CampaignID != nil {
+		add("campaign_id = $%d", *filter.CampaignID)
+	}
+	if filter.CreatedFrom != nil {
+		add("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedUntil != nil {
+		add("created_at < $%d", *filter.CreatedUntil)
+	}
+	if filter.TenantID != nil {
+		add("tenant_id = $%d", *filter.TenantID)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
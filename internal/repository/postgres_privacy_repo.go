@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresPrivacyRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresPrivacyRepository(db *sqlx.DB) *PostgresPrivacyRepository {
+	return &PostgresPrivacyRepository{db: db}
+}
+
+func (r *PostgresPrivacyRepository) Forget(ctx context.Context, number string) (domain.ForgetResult, error) {
+	var result domain.ForgetResult
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	steps := []struct {
+		count *int
+		query string
+		args  []any
+	}{
+		{&result.Cancelled, `
+          UPDATE messages
+          SET status = 'cancelled',
+              updated_at = NOW()
+          WHERE "to" = $1 AND status = 'pending'
+        `, []any{number}},
+		{&result.Messages, `
+          UPDATE messages
+          SET "to" = $2,
+              content = '',
+              key_id = NULL,
+              dek = NULL,
+              content_hash = NULL,
+              last_error = NULL,
+              updated_at = NOW()
+          WHERE "to" = $1
+        `, []any{number, domain.ForgottenNumber}},
+		{&result.ArchivedMessages, `
+          UPDATE messages_archive
+          SET "to" = $2,
+              content = '',
+              key_id = NULL,
+              dek = NULL,
+              content_hash = NULL,
+              last_error = NULL
+          WHERE "to" = $1
+        `, []any{number, domain.ForgottenNumber}},
+		{&result.InboundMessages, `
+          UPDATE inbound_messages
+          SET "from" = $2,
+              content = ''
+          WHERE "from" = $1
+        `, []any{number, domain.ForgottenNumber}},
+		{&result.ImportErrors, `
+          UPDATE import_errors
+          SET record = array_replace(record, $1, $2)
+          WHERE $1 = ANY(record)
+        `, []any{number, domain.ForgottenNumber}},
+		// The number is a valid E.164 number, so escaping its plus makes it a
+		// pattern; the lookahead spares longer numbers starting with it.
+		{&result.AuditEntries, `
+          UPDATE audit_log
+          SET target = regexp_replace(target, '\' || $1 || '(?![0-9])', $2, 'g'),
+              params = regexp_replace(params::text, '\' || $1 || '(?![0-9])', $2, 'g')::jsonb
+          WHERE strpos(target, $1) > 0 OR strpos(params::text, $1) > 0
+        `, []any{number, domain.ForgottenNumber}},
+	}
+
+	for _, step := range steps {
+		res, err := tx.ExecContext(ctx, step.query, step.args...)
+		if err != nil {
+			return result, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return result, err
+		}
+		*step.count = int(n)
+	}
+
+	return result, tx.Commit()
+}
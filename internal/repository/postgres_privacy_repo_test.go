@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// placeholder matches the positional parameters of a Postgres statement.
+var placeholder = regexp.MustCompile(`\$(\d+)`)
+
+// recordingConnector is a database/sql driver that, like Postgres, rejects
+// statements executed with a different number of arguments than they take.
+// Every statement affects one row.
+type recordingConnector struct {
+	execs [][]driver.Value
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return recordingConn{c}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver {
+	return nil
+}
+
+type recordingConn struct {
+	c *recordingConnector
+}
+
+func (conn recordingConn) Prepare(query string) (driver.Stmt, error) {
+	inputs := 0
+	for _, m := range placeholder.FindAllStringSubmatch(query, -1) {
+		n, _ := strconv.Atoi(m[1])
+		inputs = max(inputs, n)
+	}
+	return recordingStmt{c: conn.c, inputs: inputs}, nil
+}
+
+func (conn recordingConn) Close() error {
+	return nil
+}
+
+func (conn recordingConn) Begin() (driver.Tx, error) {
+	return recordingTx{}, nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error {
+	return nil
+}
+
+func (recordingTx) Rollback() error {
+	return nil
+}
+
+type recordingStmt struct {
+	c      *recordingConnector
+	inputs int
+}
+
+func (s recordingStmt) Close() error {
+	return nil
+}
+
+func (s recordingStmt) NumInput() int {
+	return s.inputs
+}
+
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.execs = append(s.c.execs, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s recordingStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func TestForget(t *testing.T) {
+	connector := &recordingConnector{}
+	db := sqlx.NewDb(sql.OpenDB(connector), "postgres")
+	defer db.Close()
+
+	repo := repository.NewPostgresPrivacyRepository(db)
+
+	result, err := repo.Forget(context.Background(), "+905551234567")
+	if err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+
+	expected := domain.ForgetResult{Cancelled: 1, Messages: 1, ArchivedMessages: 1, InboundMessages: 1, ImportErrors: 1, AuditEntries: 1}
+	if result != expected {
+		t.Errorf("Forget() = %+v, expected %+v", result, expected)
+	}
+
+	for _, args := range connector.execs {
+		if args[0] != "+905551234567" {
+			t.Errorf("statement args = %v, expected the number first", args)
+		}
+		if len(args) > 1 && !slices.Equal(args[1:], []driver.Value{domain.ForgottenNumber}) {
+			t.Errorf("statement args = %v, expected the forgotten placeholder second", args)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// costGroupColumns renders each cost group as a column, and the NULL that
+// takes its place when the report is not grouped by it.
+var costGroupColumns = []struct {
+	group  string
+	name   string
+	expr   string
+	absent string
+}{
+	{domain.CostGroupDay, "day", "date_trunc('day', sent_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'", "NULL::timestamptz"},
+	{domain.CostGroupCampaign, "campaign_id", "campaign_id", "NULL::bigint"},
+	{domain.CostGroupTenant, "tenant_id", "tenant_id", "NULL::bigint"},
+	{domain.CostGroupProvider, "provider", "provider", "NULL::varchar"},
+}
+
+// statsGroupColumns renders each stats group as the column it fills. Both
+// time groups fill the bucket column.
+var statsGroupColumns = map[string]struct {
+	name string
+	expr string
+}{
+	domain.StatsGroupHour:     {"bucket", "date_trunc('hour', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'"},
+	domain.StatsGroupDay:      {"bucket", "date_trunc('day', created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'"},
+	domain.StatsGroupCampaign: {"campaign_id", "campaign_id"},
+	domain.StatsGroupTenant:   {"tenant_id", "tenant_id"},
+}
+
+type PostgresReportRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresReportRepository(db *sqlx.DB) *PostgresReportRepository {
+	return &PostgresReportRepository{db: db}
+}
+
+func (r *PostgresReportRepository) Cost(ctx context.Context, filter domain.CostFilter) ([]domain.CostRecord, error) {
+	var args []any
+	conditions := []string{"sent_at IS NOT NULL", "segments IS NOT NULL"}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.From != nil {
+		add("sent_at >= $%d", *filter.From)
+	}
+	if filter.Until != nil {
+		add("sent_at < $%d", *filter.Until)
+	}
+	if filter.TenantID != nil {
+		add("tenant_id = $%d", *filter.TenantID)
+	}
+	if filter.CampaignID != nil {
+		add("campaign_id = $%d", *filter.CampaignID)
+	}
+
+	var columns, groups, order []string
+	for _, c := range costGroupColumns {
+		if !slices.Contains(filter.GroupBy, c.group) {
+			columns = append(columns, c.absent+" AS "+c.name)
+			continue
+		}
+		columns = append(columns, c.expr+" AS "+c.name)
+		groups = append(groups, c.expr)
+		order = append(order, c.expr+" NULLS FIRST")
+	}
+
+	groupBy := ""
+	if len(groups) > 0 {
+		groupBy = "GROUP BY " + strings.Join(groups, ", ") + "\n      ORDER BY " + strings.Join(order, ", ")
+	}
+
+	var records []domain.CostRecord
+	err := r.db.SelectContext(ctx, &records, fmt.Sprintf(`
+      SELECT %s,
+             COUNT(*) AS messages,
+             COALESCE(SUM(segments), 0) AS segments,
+             COALESCE(SUM(segments) FILTER (WHERE unit_price IS NULL), 0) AS unpriced_segments,
+             COALESCE(SUM(segments * unit_price), 0) AS cost
+      FROM messages
+      WHERE %s
+      %s
+    `, strings.Join(columns, ",\n             "), strings.Join(conditions, " AND "), groupBy), args...)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *PostgresReportRepository) Stats(ctx context.Context, filter domain.StatsFilter) ([]domain.Stats, error) {
+	args := []any{filter.From, filter.Until}
+	conditions := []string{"created_at >= $1", "created_at < $2"}
+
+	if filter.TenantID != nil {
+		args = append(args, *filter.TenantID)
+		conditions = append(conditions, fmt.Sprintf("tenant_id = $%d", len(args)))
+	}
+
+	columns := map[string]string{
+		"bucket":      "NULL::timestamptz AS bucket",
+		"campaign_id": "NULL::bigint AS campaign_id",
+		"tenant_id":   "NULL::bigint AS tenant_id",
+	}
+	groupBy := ""
+	if c, ok := statsGroupColumns[filter.GroupBy]; ok {
+		columns[c.name] = c.expr + " AS " + c.name
+		groupBy = "GROUP BY 1, 2, 3\n      ORDER BY 1, 2 NULLS FIRST, 3 NULLS FIRST"
+	}
+
+	var stats []domain.Stats
+	err := r.db.SelectContext(ctx, &stats, fmt.Sprintf(`
+      SELECT %s,
+             %s,
+             %s,
+             COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+             COUNT(*) FILTER (WHERE status = 'processing') AS processing,
+             COUNT(*) FILTER (WHERE status = 'sent') AS sent,
+             COUNT(*) FILTER (WHERE status = 'delivered') AS delivered,
+             COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+             COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled,
+             COUNT(*) FILTER (WHERE status = 'suppressed') AS suppressed,
+             COUNT(*) FILTER (WHERE status = 'duplicate') AS duplicate,
+             percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM sent_at - created_at))
+                 FILTER (WHERE sent_at IS NOT NULL) AS latency_p50,
+             percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM sent_at - created_at))
+                 FILTER (WHERE sent_at IS NOT NULL) AS latency_p95
+      FROM messages
+      WHERE %s
+      %s
+    `, columns["bucket"], columns["campaign_id"], columns["tenant_id"], strings.Join(conditions, " AND "), groupBy), args...)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *PostgresReportRepository) Backlog(ctx context.Context, tenantID *int64) (domain.Backlog, error) {
+	var backlog domain.Backlog
+	err := r.db.GetContext(ctx, &backlog, `
+      SELECT COUNT(*) AS pending, MIN(created_at) AS oldest_pending_at
+      FROM messages
+      WHERE status = 'pending' AND `+tenantCondition(1)+`
+    `, tenantID)
+	return backlog, err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const retentionRunColumns = `id, mode, cutoff, purged, by_status::text AS by_status, batches, error, started_at, finished_at`
+
+type retentionRunRow struct {
+	ID         int64     `db:"id"`
+	Mode       string    `db:"mode"`
+	Cutoff     time.Time `db:"cutoff"`
+	Purged     int       `db:"purged"`
+	ByStatus   string    `db:"by_status"`
+	Batches    int       `db:"batches"`
+	Error      *string   `db:"error"`
+	StartedAt  time.Time `db:"started_at"`
+	FinishedAt time.Time `db:"finished_at"`
+}
+
+func (r retentionRunRow) toDomain() (domain.RetentionRun, error) {
+	run := domain.RetentionRun{
+		ID:         r.ID,
+		Mode:       r.Mode,
+		Cutoff:     r.Cutoff,
+		Purged:     r.Purged,
+		Batches:    r.Batches,
+		Error:      r.Error,
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+	}
+	err := json.Unmarshal([]byte(r.ByStatus), &run.ByStatus)
+	return run, err
+}
+
+type PostgresRetentionRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresRetentionRepository(db *sqlx.DB) *PostgresRetentionRepository {
+	return &PostgresRetentionRepository{db: db}
+}
+
+func (r *PostgresRetentionRepository) PurgeBatch(
+	ctx context.Context,
+	statuses []domain.MessageStatus,
+	cutoff time.Time,
+	limit int,
+	archive bool,
+) (map[domain.MessageStatus]int, error) {
+
+	names := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		names = append(names, string(status))
+	}
+
+	archived := ""
+	if archive {
+		archived = `, archived AS (
+            INSERT INTO messages_archive (` + messageColumns + `)
+            SELECT ` + messageColumns + ` FROM purged
+        )`
+	}
+
+	var rows []struct {
+		Status domain.MessageStatus `db:"status"`
+		Count  int                  `db:"count"`
+	}
+	err := r.db.SelectContext(ctx, &rows, `
+        WITH batch AS (
+            SELECT id
+            FROM messages
+            WHERE status = ANY($1) AND COALESCE(sent_at, updated_at) < $2
+            LIMIT $3
+            FOR UPDATE SKIP LOCKED
+        ), purged AS (
+            DELETE FROM messages m
+            USING batch
+            WHERE m.id = batch.id
+            RETURNING m.*
+        )`+archived+`
+        SELECT status, COUNT(*) AS count
+        FROM purged
+        GROUP BY status
+    `, pq.Array(names), cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := make(map[domain.MessageStatus]int, len(rows))
+	for _, row := range rows {
+		purged[row.Status] = row.Count
+	}
+	return purged, nil
+}
+
+func (r *PostgresRetentionRepository) CreateRun(ctx context.Context, run domain.RetentionRun) (domain.RetentionRun, error) {
+	byStatus, err := json.Marshal(run.ByStatus)
+	if err != nil {
+		return run, err
+	}
+
+	var row retentionRunRow
+	err = r.db.GetContext(ctx, &row, `
+      INSERT INTO retention_runs (mode, cutoff, purged, by_status, batches, error, started_at)
+      VALUES ($1, $2, $3, $4::jsonb, $5, $6, $7)
+      RETURNING `+retentionRunColumns,
+		run.Mode, run.Cutoff, run.Purged, string(byStatus), run.Batches, run.Error, run.StartedAt)
+	if err != nil {
+		return run, err
+	}
+	return row.toDomain()
+}
+
+func (r *PostgresRetentionRepository) ListRuns(ctx context.Context, limit, offset int) ([]domain.RetentionRun, error) {
+	var rows []retentionRunRow
+	err := r.db.SelectContext(ctx, &rows, `
+      SELECT `+retentionRunColumns+`
+      FROM retention_runs
+      ORDER BY started_at DESC
+      LIMIT $1 OFFSET $2
+    `, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]domain.RetentionRun, 0, len(rows))
+	for _, row := range rows {
+		run, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type PostgresSchedulerStateRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSchedulerStateRepository(db *sqlx.DB) *PostgresSchedulerStateRepository {
+	return &PostgresSchedulerStateRepository{db: db}
+}
+
+func (r *PostgresSchedulerStateRepository) Get(ctx context.Context) (domain.SchedulerState, error) {
+	var state domain.SchedulerState
+	err := r.db.GetContext(ctx, &state, `
+      SELECT running, updated_by, reason, updated_at
+      FROM scheduler_state
+      WHERE id = 1
+    `)
+	return state, err
+}
+
+func (r *PostgresSchedulerStateRepository) SetRunning(ctx context.Context, running bool, updatedBy string, reason *string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+      UPDATE scheduler_state
+      SET running = $1,
+          updated_by = $2,
+          reason = $3,
+          updated_at = NOW()
+      WHERE id = 1 AND running <> $1
+    `, running, updatedBy, reason)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const suppressionColumns = `number, reason, source, created_at, updated_at`
+
+type PostgresSuppressionRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresSuppressionRepository(db *sqlx.DB) *PostgresSuppressionRepository {
+	return &PostgresSuppressionRepository{db: db}
+}
+
+func (r *PostgresSuppressionRepository) Create(ctx context.Context, s domain.Suppression) (domain.Suppression, error) {
+	var created domain.Suppression
+	err := r.db.GetContext(ctx, &created, `
+      INSERT INTO suppressions (number, reason, source)
+      VALUES ($1, $2, $3)
+      RETURNING `+suppressionColumns, s.Number, s.Reason, s.Source)
+	if isUniqueViolation(err) {
+		return created, ErrConflict
+	}
+	return created, err
+}
+
+func (r *PostgresSuppressionRepository) CreateIfMissing(ctx context.Context, s domain.Suppression) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+      INSERT INTO suppressions (number, reason, source)
+      VALUES ($1, $2, $3)
+      ON CONFLICT (number) DO NOTHING
+    `, s.Number, s.Reason, s.Source)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (r *PostgresSuppressionRepository) Get(ctx context.Context, number string) (domain.Suppression, error) {
+	var s domain.Suppression
+	err := r.db.GetContext(ctx, &s, `
+      SELECT `+suppressionColumns+`
+      FROM suppressions
+      WHERE number = $1
+    `, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	return s, err
+}
+
+func (r *PostgresSuppressionRepository) List(ctx context.Context, limit, offset int) ([]domain.Suppression, error) {
+	var suppressions []domain.Suppression
+	err := r.db.SelectContext(ctx, &suppressions, `
+      SELECT `+suppressionColumns+`
+      FROM suppressions
+      ORDER BY created_at DESC
+      LIMIT $1 OFFSET $2
+    `, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return suppressions, nil
+}
+
+func (r *PostgresSuppressionRepository) Update(ctx context.Context, number, reason string) (domain.Suppression, error) {
+	var s domain.Suppression
+	err := r.db.GetContext(ctx, &s, `
+      UPDATE suppressions
+      SET reason = $2,
+          updated_at = NOW()
+      WHERE number = $1
+      RETURNING `+suppressionColumns, number, reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, ErrNotFound
+	}
+	return s, err
+}
+
+func (r *PostgresSuppressionRepository) Delete(ctx context.Context, number string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM suppressions WHERE number = $1`, number)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresSuppressionRepository) IsSuppressed(ctx context.Context, number string) (bool, error) {
+	var suppressed bool
+	err := r.db.GetContext(ctx, &suppressed, `
+      SELECT EXISTS (SELECT 1 FROM suppressions WHERE number = $1)
+    `, number)
+	return suppressed, err
+}
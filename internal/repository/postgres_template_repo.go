@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const templateColumns = `id, name, locale, body, tenant_id, created_at, updated_at`
+
+type PostgresTemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresTemplateRepository(db *sqlx.DB) *PostgresTemplateRepository {
+	return &PostgresTemplateRepository{db: db}
+}
+
+func (r *PostgresTemplateRepository) Create(ctx context.Context, t domain.Template) (domain.Template, error) {
+	var created domain.Template
+	err := r.db.GetContext(ctx, &created, `
+      INSERT INTO templates (name, locale, body, tenant_id)
+      VALUES ($1, $2, $3, $4)
+      RETURNING `+templateColumns, t.Name, t.Locale, t.Body, t.TenantID)
+	if isUniqueViolation(err) {
+		return created, ErrConflict
+	}
+	if isForeignKeyViolation(err) {
+		return created, ErrInvalidReference
+	}
+	return created, err
+}
+
+func (r *PostgresTemplateRepository) Get(ctx context.Context, id int64, tenantID *int64) (domain.Template, error) {
+	var t domain.Template
+	err := r.db.GetContext(ctx, &t, `
+      SELECT `+templateColumns+`
+      FROM templates
+      WHERE id = $1 AND `+tenantCondition(2)+`
+    `, id, tenantID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, ErrNotFound
+	}
+	return t, err
+}
+
+func (r *PostgresTemplateRepository) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Template, error) {
+	var templates []domain.Template
+	err := r.db.SelectContext(ctx, &templates, `
+      SELECT `+templateColumns+`
+      FROM templates
+      WHERE `+tenantCondition(3)+`
+      ORDER BY name, locale
+      LIMIT $1 OFFSET $2
+    `, limit, offset, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *PostgresTemplateRepository) Delete(ctx context.Context, id int64, tenantID *int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM templates WHERE id = $1 AND `+tenantCondition(2), id, tenantID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const tenantColumns = `id, name, webhook_url, webhook_auth_key, provider, daily_quota, monthly_quota, created_at, updated_at`
+
+type PostgresTenantRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresTenantRepository(db *sqlx.DB) *PostgresTenantRepository {
+	return &PostgresTenantRepository{db: db}
+}
+
+func (r *PostgresTenantRepository) Create(ctx context.Context, t domain.Tenant) (domain.Tenant, error) {
+	var created domain.Tenant
+	err := r.db.GetContext(ctx, &created, `
+      INSERT INTO tenants (name, webhook_url, webhook_auth_key, provider, daily_quota, monthly_quota)
+      VALUES ($1, $2, $3, $4, $5, $6)
+      RETURNING `+tenantColumns, t.Name, t.WebhookURL, t.WebhookAuthKey, t.Provider, t.Daily, t.Monthly)
+	if isUniqueViolation(err) {
+		return created, ErrConflict
+	}
+	return created, err
+}
+
+func (r *PostgresTenantRepository) Get(ctx context.Context, id int64) (domain.Tenant, error) {
+	var t domain.Tenant
+	err := r.db.GetContext(ctx, &t, `
+      SELECT `+tenantColumns+`
+      FROM tenants
+      WHERE id = $1
+    `, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return t, ErrNotFound
+	}
+	return t, err
+}
+
+func (r *PostgresTenantRepository) List(ctx context.Context, limit, offset int) ([]domain.Tenant, error) {
+	var tenants []domain.Tenant
+	err := r.db.SelectContext(ctx, &tenants, `
+      SELECT `+tenantColumns+`
+      FROM tenants
+      ORDER BY name
+      LIMIT $1 OFFSET $2
+    `, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+func (r *PostgresTenantRepository) Update(ctx context.Context, t domain.Tenant) (domain.Tenant, error) {
+	var updated domain.Tenant
+	err := r.db.GetContext(ctx, &updated, `
+      UPDATE tenants
+      SET webhook_url = $2,
+          webhook_auth_key = $3,
+          provider = $4,
+          daily_quota = $5,
+          monthly_quota = $6,
+          updated_at = NOW()
+      WHERE id = $1
+      RETURNING `+tenantColumns, t.ID, t.WebhookURL, t.WebhookAuthKey, t.Provider, t.Daily, t.Monthly)
+	if errors.Is(err, sql.ErrNoRows) {
+		return updated, ErrNotFound
+	}
+	return updated, err
+}
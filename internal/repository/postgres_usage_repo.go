@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+const dateLayout = "2006-01-02"
+
+type PostgresUsageRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostgresUsageRepository(db *sqlx.DB) *PostgresUsageRepository {
+	return &PostgresUsageRepository{db: db}
+}
+
+// quotaSubject is a tenant or API key whose quota a charge counts against.
+type quotaSubject struct {
+	kind   string
+	id     int64
+	column string
+	quota  domain.Quota
+}
+
+func (r *PostgresUsageRepository) Consume(ctx context.Context, charge domain.UsageCharge) ([]domain.QuotaUsage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the tenant and key rows serialises concurrent charges against
+	// the same quotas.
+	var subjects []quotaSubject
+	if charge.TenantID != nil {
+		s := quotaSubject{kind: domain.QuotaSubjectTenant, id: *charge.TenantID, column: "tenant_id"}
+		if err := tx.GetContext(ctx, &s.quota, `
+          SELECT daily_quota, monthly_quota FROM tenants WHERE id = $1 FOR UPDATE
+        `, s.id); err != nil {
+			return nil, lockError(err)
+		}
+		subjects = append(subjects, s)
+	}
+	if charge.APIKeyID != nil {
+		s := quotaSubject{kind: domain.QuotaSubjectAPIKey, id: *charge.APIKeyID, column: "api_key_id"}
+		if err := tx.GetContext(ctx, &s.quota, `
+          SELECT daily_quota, monthly_quota FROM api_keys WHERE id = $1 FOR UPDATE
+        `, s.id); err != nil {
+			return nil, lockError(err)
+		}
+		subjects = append(subjects, s)
+	}
+
+	at := charge.At.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		usages   []domain.QuotaUsage
+		exceeded bool
+	)
+
+	for _, s := range subjects {
+		periods := []struct {
+			name  string
+			limit *int
+			from  time.Time
+			until time.Time
+		}{
+			{domain.UsagePeriodDay, s.quota.Daily, day, day.AddDate(0, 0, 1)},
+			{domain.UsagePeriodMonth, s.quota.Monthly, month, month.AddDate(0, 1, 0)},
+		}
+
+		for _, p := range periods {
+			if p.limit == nil {
+				continue
+			}
+
+			var used int
+			if err := tx.GetContext(ctx, &used, fmt.Sprintf(`
+              SELECT COALESCE(SUM(segments), 0)
+              FROM usage_ledger
+              WHERE %s = $1 AND day >= $2::date AND day < $3::date
+            `, s.column), s.id, p.from.Format(dateLayout), p.until.Format(dateLayout)); err != nil {
+				return nil, err
+			}
+
+			usage := domain.QuotaUsage{
+				Subject:   s.kind,
+				SubjectID: s.id,
+				Period:    p.name,
+				Limit:     *p.limit,
+				Used:      used,
+				ResetAt:   p.until,
+			}
+			if used+charge.Segments > *p.limit {
+				usage.Exceeded = true
+				exceeded = true
+			}
+			usages = append(usages, usage)
+		}
+	}
+
+	if exceeded {
+		return usages, ErrQuotaExceeded
+	}
+
+	_, err = tx.ExecContext(ctx, `
+      INSERT INTO usage_ledger (tenant_id, api_key_id, day, messages, segments)
+      VALUES ($1, $2, $3::date, $4, $5)
+      ON CONFLICT ((COALESCE(tenant_id, 0)), (COALESCE(api_key_id, 0)), day) DO UPDATE
+      SET messages = usage_ledger.messages + EXCLUDED.messages,
+          segments = usage_ledger.segments + EXCLUDED.segments,
+          updated_at = NOW()
+    `, charge.TenantID, charge.APIKeyID, day.Format(dateLayout), charge.Messages, charge.Segments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for i := range usages {
+		usages[i].Used += charge.Segments
+	}
+
+	return usages, nil
+}
+
+func lockError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidReference
+	}
+	return err
+}
+
+func (r *PostgresUsageRepository) Release(ctx context.Context, charge domain.UsageCharge) error {
+	_, err := r.db.ExecContext(ctx, `
+      UPDATE usage_ledger
+      SET messages = GREATEST(messages - $4, 0),
+          segments = GREATEST(segments - $5, 0),
+          updated_at = NOW()
+      WHERE COALESCE(tenant_id, 0) = COALESCE($1::bigint, 0)
+        AND COALESCE(api_key_id, 0) = COALESCE($2::bigint, 0)
+        AND day = $3::date
+    `, charge.TenantID, charge.APIKeyID, charge.At.UTC().Format(dateLayout), charge.Messages, charge.Segments)
+	return err
+}
+
+func (r *PostgresUsageRepository) List(ctx context.Context, filter domain.UsageFilter) ([]domain.UsageRecord, error) {
+	args := []any{filter.Period}
+	conditions := []string{"TRUE"}
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.From != nil {
+		add("day >= $%d::date", filter.From.UTC().Format(dateLayout))
+	}
+	if filter.Until != nil {
+		add("day < $%d::date", filter.Until.UTC().Format(dateLayout))
+	}
+	if filter.TenantID != nil {
+		add("tenant_id = $%d", *filter.TenantID)
+	}
+	if filter.APIKeyID != nil {
+		add("api_key_id = $%d", *filter.APIKeyID)
+	}
+
+	var records []domain.UsageRecord
+	err := r.db.SelectContext(ctx, &records, fmt.Sprintf(`
+      SELECT date_trunc($1, day::timestamp) AT TIME ZONE 'UTC' AS period_start,
+             tenant_id,
+             api_key_id,
+             SUM(messages) AS messages,
+             SUM(segments) AS segments
+      FROM usage_ledger
+      WHERE %s
+      GROUP BY 1, tenant_id, api_key_id
+      ORDER BY 1 DESC, tenant_id NULLS FIRST, api_key_id NULLS FIRST
+    `, strings.Join(conditions, " AND ")), args...)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type PrivacyRepository interface {
+	// Forget anonymizes every message, archived message, reply and rejected
+	// import row of a phone number in one transaction. Pending messages to
+	// the number are cancelled first. The suppression list is kept, so an
+	// opted out number stays opted out.
+	Forget(ctx context.Context, number string) (domain.ForgetResult, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type ReportRepository interface {
+	// Cost sums the cost of sent messages by the dimensions in
+	// filter.GroupBy, which must be valid cost groups.
+	Cost(ctx context.Context, filter domain.CostFilter) ([]domain.CostRecord, error)
+	// Stats counts the messages created in the filter's range by status,
+	// one row per group or a single row when filter.GroupBy is empty. Rates
+	// are left to the caller.
+	Stats(ctx context.Context, filter domain.StatsFilter) ([]domain.Stats, error)
+	// Backlog reports the pending messages of tenantID, or of every tenant
+	// when it is nil.
+	Backlog(ctx context.Context, tenantID *int64) (domain.Backlog, error)
+}
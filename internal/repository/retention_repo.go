@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type RetentionRepository interface {
+	// PurgeBatch removes up to limit messages with one of statuses that
+	// finished before cutoff, copying them to messages_archive first when
+	// archive is set. Messages locked by others are skipped. It returns the
+	// number of purged messages by status.
+	PurgeBatch(ctx context.Context, statuses []domain.MessageStatus, cutoff time.Time, limit int, archive bool) (map[domain.MessageStatus]int, error)
+	CreateRun(ctx context.Context, run domain.RetentionRun) (domain.RetentionRun, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]domain.RetentionRun, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type SchedulerStateRepository interface {
+	Get(ctx context.Context) (domain.SchedulerState, error)
+	// SetRunning updates the desired state and reports whether it changed.
+	SetRunning(ctx context.Context, running bool, updatedBy string, reason *string) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type SuppressionRepository interface {
+	Create(ctx context.Context, s domain.Suppression) (domain.Suppression, error)
+	// CreateIfMissing adds s unless the number is already suppressed and
+	// reports whether it was added.
+	CreateIfMissing(ctx context.Context, s domain.Suppression) (bool, error)
+	Get(ctx context.Context, number string) (domain.Suppression, error)
+	List(ctx context.Context, limit, offset int) ([]domain.Suppression, error)
+	Update(ctx context.Context, number, reason string) (domain.Suppression, error)
+	Delete(ctx context.Context, number string) error
+	IsSuppressed(ctx context.Context, number string) (bool, error)
+}
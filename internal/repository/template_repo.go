@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t domain.Template) (domain.Template, error)
+	// Get, List and Delete only see templates of tenantID unless it is nil.
+	Get(ctx context.Context, id int64, tenantID *int64) (domain.Template, error)
+	List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Template, error)
+	Delete(ctx context.Context, id int64, tenantID *int64) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type TenantRepository interface {
+	Create(ctx context.Context, t domain.Tenant) (domain.Tenant, error)
+	Get(ctx context.Context, id int64) (domain.Tenant, error)
+	List(ctx context.Context, limit, offset int) ([]domain.Tenant, error)
+	Update(ctx context.Context, t domain.Tenant) (domain.Tenant, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+)
+
+type UsageRepository interface {
+	// Consume adds charge to the ledger unless it would exceed a quota of
+	// the charged tenant or API key. It returns the affected quotas, and
+	// ErrQuotaExceeded without recording anything when one is exceeded.
+	Consume(ctx context.Context, charge domain.UsageCharge) ([]domain.QuotaUsage, error)
+	// Release reverts a charge whose message could not be stored.
+	Release(ctx context.Context, charge domain.UsageCharge) error
+	List(ctx context.Context, filter domain.UsageFilter) ([]domain.UsageRecord, error)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+const (
+	apiKeyPrefix       = "ins_"
+	apiKeyPrefixLength = 12
+
+	minAdminKeyLength   = 32
+	placeholderAdminKey = "change-me-local-admin-key"
+
+	PrincipalAPIKey    = "api_key"
+	PrincipalBootstrap = "bootstrap"
+)
+
+var (
+	ErrUnauthenticated = errors.New("invalid or missing credentials")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrInvalidAPIKey   = errors.New("invalid api key")
+	ErrGrantExceeded   = errors.New("cannot grant more than the caller holds")
+)
+
+type APIKeyService struct {
+	repo repository.APIKeyRepository
+	// adminKeyHash is the hash of the bootstrap key from the config, which
+	// holds every scope and is used to create the first keys.
+	adminKeyHash string
+	log          *zap.SugaredLogger
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, adminKey string, log *zap.SugaredLogger) *APIKeyService {
+	s := &APIKeyService{
+		repo: repo,
+		log:  log,
+	}
+	if adminKey != "" {
+		s.adminKeyHash = hashKey(adminKey)
+	}
+	return s
+}
+
+// ValidateAdminKey rejects a bootstrap key that is the old placeholder or
+// shorter than minAdminKeyLength. An empty key disables it; dev accepts any.
+func ValidateAdminKey(adminKey string, dev bool) error {
+	if adminKey == "" || dev {
+		return nil
+	}
+	if adminKey == placeholderAdminKey {
+		return fmt.Errorf("admin key is the placeholder value")
+	}
+	if len(adminKey) < minAdminKeyLength {
+		return fmt.Errorf("admin key must be at least %d characters", minAdminKeyLength)
+	}
+	return nil
+}
+
+// Create generates a new key. The plaintext key is only returned here. Keys
+// bound to a tenant may only hold tenant scopes. The new key can hold neither
+// a scope nor a quota beyond those of the granter.
+func (s *APIKeyService) Create(ctx context.Context, granter domain.Principal, name string, scopes []string, tenantID *int64, quota domain.Quota, createdBy string) (domain.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.APIKey{}, "", fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+	}
+	if len(scopes) == 0 {
+		return domain.APIKey{}, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKey)
+	}
+	if err := ValidateQuota(quota); err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("%w: %w", ErrInvalidAPIKey, err)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(domain.Scopes, scope) {
+			return domain.APIKey{}, "", fmt.Errorf("%w: unknown scope %q", ErrInvalidAPIKey, scope)
+		}
+		if tenantID != nil && !slices.Contains(domain.TenantScopes, scope) {
+			return domain.APIKey{}, "", fmt.Errorf("%w: scope %q cannot be granted to a tenant key", ErrInvalidAPIKey, scope)
+		}
+		if !granter.HasScope(scope) {
+			return domain.APIKey{}, "", fmt.Errorf("%w: missing scope %q", ErrGrantExceeded, scope)
+		}
+	}
+	if !quotaWithin(quota.Daily, granter.Quota.Daily) || !quotaWithin(quota.Monthly, granter.Quota.Monthly) {
+		return domain.APIKey{}, "", fmt.Errorf("%w: quota exceeds the caller's quota", ErrGrantExceeded)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.APIKey{}, "", fmt.Errorf("generate key: %w", err)
+	}
+	plaintext := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key, err := s.repo.Create(ctx, domain.APIKey{
+		Name:      name,
+		Prefix:    plaintext[:apiKeyPrefixLength],
+		KeyHash:   hashKey(plaintext),
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		TenantID:  tenantID,
+		Quota:     quota,
+	})
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return key, "", ErrUnknownTenant
+	}
+	if err != nil {
+		return key, "", err
+	}
+
+	s.log.Infow("API key created", "keyID", key.ID, "name", key.Name, "scopes", key.Scopes, "tenantID", key.TenantID, "by", createdBy)
+
+	return key, plaintext, nil
+}
+
+// quotaWithin reports whether limit is no looser than ceiling. A nil limit is
+// unlimited.
+func quotaWithin(limit, ceiling *int) bool {
+	if ceiling == nil {
+		return true
+	}
+	return limit != nil && *limit <= *ceiling
+}
+
+func (s *APIKeyService) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.APIKey, error) {
+	return s.repo.List(ctx, tenantID, limit, offset)
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, id int64, tenantID *int64) (domain.APIKey, error) {
+	key, err := s.repo.Revoke(ctx, id, tenantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return key, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return key, err
+	}
+
+	s.log.Infow("API key revoked", "keyID", key.ID, "name", key.Name)
+
+	return key, nil
+}
+
+// Authenticate resolves a bearer token to the principal it belongs to.
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	if token == "" {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	hash := hashKey(token)
+
+	if s.adminKeyHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.adminKeyHash)) == 1 {
+		return domain.Principal{
+			Type:   PrincipalBootstrap,
+			ID:     "admin",
+			Name:   "bootstrap-admin",
+			Scopes: domain.Scopes,
+		}, nil
+	}
+
+	key, err := s.repo.Authenticate(ctx, hash)
+	if errors.Is(err, repository.ErrNotFound) {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	return domain.Principal{
+		Type:     PrincipalAPIKey,
+		ID:       strconv.FormatInt(key.ID, 10),
+		Name:     key.Name,
+		Scopes:   key.Scopes,
+		TenantID: key.TenantID,
+		Quota:    key.Quota,
+	}, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+func TestValidateAdminKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		dev     bool
+		wantErr bool
+	}{
+		{name: "Empty", key: ""},
+		{name: "Long_Key", key: strings.Repeat("k", 32)},
+		{name: "Placeholder", key: "change-me-local-admin-key", wantErr: true},
+		{name: "Short_Key", key: strings.Repeat("k", 31), wantErr: true},
+		{name: "Placeholder_In_Dev", key: "change-me-local-admin-key", dev: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateAdminKey(tt.key, tt.dev)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAdminKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type fakeAPIKeyRepo struct {
+	repository.APIKeyRepository
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	key.ID = 1
+	return key, nil
+}
+
+func TestCreateAPIKeyGrant(t *testing.T) {
+	limit := func(n int) *int { return &n }
+
+	admin := domain.Principal{
+		Type:   service.PrincipalAPIKey,
+		Scopes: []string{domain.ScopeKeysAdmin, domain.ScopeMessagesRead},
+		Quota:  domain.Quota{Daily: limit(100)},
+	}
+
+	tests := []struct {
+		name        string
+		granter     domain.Principal
+		scopes      []string
+		quota       domain.Quota
+		expectedErr error
+	}{
+		{name: "Held_Scope", granter: admin, scopes: []string{domain.ScopeMessagesRead}, quota: domain.Quota{Daily: limit(100)}},
+		{name: "Tighter_Quota", granter: admin, scopes: []string{domain.ScopeMessagesRead}, quota: domain.Quota{Daily: limit(10), Monthly: limit(50)}},
+		{name: "Missing_Scope", granter: admin, scopes: []string{domain.ScopeSchedulerAdmin}, quota: domain.Quota{Daily: limit(100)}, expectedErr: service.ErrGrantExceeded},
+		{name: "Looser_Quota", granter: admin, scopes: []string{domain.ScopeMessagesRead}, quota: domain.Quota{Daily: limit(101)}, expectedErr: service.ErrGrantExceeded},
+		{name: "No_Quota", granter: admin, scopes: []string{domain.ScopeMessagesRead}, expectedErr: service.ErrGrantExceeded},
+		{name: "Unlimited_Granter", granter: domain.Principal{Type: service.PrincipalBootstrap, Scopes: domain.Scopes}, scopes: []string{domain.ScopeSchedulerAdmin}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewAPIKeyService(&fakeAPIKeyRepo{}, "", zap.NewNop().Sugar())
+
+			_, _, err := svc.Create(context.Background(), tt.granter, "crm", tt.scopes, nil, tt.quota, "test")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Create() error = %v, expected %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+// AuditRecord describes an action to add to the audit log.
+type AuditRecord struct {
+	Actor    string
+	Action   string
+	Target   string
+	Params   any
+	SourceIP string
+}
+
+type AuditService struct {
+	repo repository.AuditRepository
+	log  *zap.SugaredLogger
+}
+
+func NewAuditService(repo repository.AuditRepository, log *zap.SugaredLogger) *AuditService {
+	return &AuditService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *AuditService) Record(ctx context.Context, rec AuditRecord) error {
+	entry := domain.AuditEntry{
+		Actor:    rec.Actor,
+		Action:   rec.Action,
+		Target:   rec.Target,
+		SourceIP: rec.SourceIP,
+	}
+
+	if rec.Params != nil {
+		params, err := json.Marshal(rec.Params)
+		if err != nil {
+			return fmt.Errorf("marshal audit params: %w", err)
+		}
+		entry.Params = params
+	}
+
+	if _, err := s.repo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter, limit, offset int) ([]domain.AuditEntry, error) {
+	return s.repo.List(ctx, filter, limit, offset)
+}
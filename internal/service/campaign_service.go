@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCampaignNotFound    = errors.New("campaign not found")
+	ErrCampaignNameMissing = errors.New("campaign name is required")
+	ErrInvalidDedupWindow  = errors.New("dedup_window_seconds must not be negative")
+)
+
+// CampaignDetails is a campaign together with its message counts.
+type CampaignDetails struct {
+	domain.Campaign
+	Stats domain.CampaignStats `json:"stats"`
+}
+
+type CampaignService struct {
+	repo repository.CampaignRepository
+	log  *zap.SugaredLogger
+}
+
+func NewCampaignService(repo repository.CampaignRepository, log *zap.SugaredLogger) *CampaignService {
+	return &CampaignService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *CampaignService) Create(ctx context.Context, c domain.Campaign) (domain.Campaign, error) {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return domain.Campaign{}, ErrCampaignNameMissing
+	}
+	if c.DedupWindowSeconds != nil && *c.DedupWindowSeconds < 0 {
+		return domain.Campaign{}, ErrInvalidDedupWindow
+	}
+
+	c, err := s.repo.Create(ctx, c)
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return c, ErrUnknownTenant
+	}
+	if err != nil {
+		return c, err
+	}
+
+	s.log.Infow("Campaign created", "campaignID", c.ID, "name", c.Name)
+
+	return c, nil
+}
+
+// List, Get, Pause and Resume only see campaigns of tenantID unless it is
+// nil.
+func (s *CampaignService) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Campaign, error) {
+	return s.repo.List(ctx, tenantID, limit, offset)
+}
+
+func (s *CampaignService) Get(ctx context.Context, id int64, tenantID *int64) (CampaignDetails, error) {
+	c, err := s.repo.Get(ctx, id, tenantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return CampaignDetails{}, ErrCampaignNotFound
+	}
+	if err != nil {
+		return CampaignDetails{}, err
+	}
+
+	stats, err := s.repo.Stats(ctx, id)
+	if err != nil {
+		return CampaignDetails{}, err
+	}
+
+	return CampaignDetails{Campaign: c, Stats: stats}, nil
+}
+
+// Pause makes the dispatcher skip pending messages of the campaign.
+func (s *CampaignService) Pause(ctx context.Context, id int64, tenantID *int64) (domain.Campaign, error) {
+	return s.setStatus(ctx, id, tenantID, domain.CampaignPaused)
+}
+
+// Resume makes pending messages of the campaign eligible for sending again.
+func (s *CampaignService) Resume(ctx context.Context, id int64, tenantID *int64) (domain.Campaign, error) {
+	return s.setStatus(ctx, id, tenantID, domain.CampaignActive)
+}
+
+func (s *CampaignService) setStatus(ctx context.Context, id int64, tenantID *int64, status domain.CampaignStatus) (domain.Campaign, error) {
+	c, err := s.repo.SetStatus(ctx, id, tenantID, status)
+	if errors.Is(err, repository.ErrNotFound) {
+		return c, ErrCampaignNotFound
+	}
+	if err != nil {
+		return c, err
+	}
+
+	s.log.Infow("Campaign status changed", "campaignID", id, "status", status)
+
+	return c, nil
+}
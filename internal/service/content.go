@@ -0,0 +1,79 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxContentLength is the maximum number of characters a message may have.
+const MaxContentLength = 160
+
+var (
+	ErrContentEmpty    = errors.New("content is empty")
+	ErrContentTooLong  = fmt.Errorf("content exceeds %d characters", MaxContentLength)
+	ErrContentEncoding = errors.New("content must be valid UTF-8 without control characters")
+	ErrInvalidNumber   = errors.New("recipient must be an E.164 phone number")
+)
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// ValidateContent checks that content can be sent as a message.
+func ValidateContent(content string) error {
+	if content == "" {
+		return ErrContentEmpty
+	}
+
+	if !utf8.ValidString(content) {
+		return ErrContentEncoding
+	}
+
+	for _, r := range content {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' {
+			return ErrContentEncoding
+		}
+	}
+
+	if utf8.RuneCountInString(content) > MaxContentLength {
+		return ErrContentTooLong
+	}
+
+	return nil
+}
+
+// ValidateNumber checks that to is an E.164 phone number.
+func ValidateNumber(to string) error {
+	if !e164.MatchString(to) {
+		return ErrInvalidNumber
+	}
+	return nil
+}
+
+// numberSeparators are stripped from numbers by NormalizeNumber.
+var numberSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizeNumber brings a number as reported by a provider into E.164 form.
+// It drops separators and reads a 00 prefix or bare digits as international
+// format. The result still has to pass ValidateNumber.
+func NormalizeNumber(number string) string {
+	number = numberSeparators.Replace(strings.TrimSpace(number))
+	switch {
+	case number == "", strings.HasPrefix(number, "+"):
+		return number
+	case strings.HasPrefix(number, "00"):
+		return "+" + number[2:]
+	default:
+		return "+" + number
+	}
+}
+
+// ContentHash returns the hex encoded SHA-256 of content.
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
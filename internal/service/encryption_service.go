@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+// EncryptionService encrypts messages stored before encryption was enabled
+// and moves messages sealed under a retired key to the active one. Only the
+// leader runs it.
+type EncryptionService struct {
+	repo       repository.MessageRepository
+	elector    LeaderElector
+	batchSize  int
+	maxBatches int
+	// done is set once a run finds nothing left. New messages are sealed
+	// with the active key as they are created, so there is nothing to do
+	// until the keyfile changes, which takes a restart.
+	done atomic.Bool
+	log  *zap.SugaredLogger
+}
+
+func NewEncryptionService(repo repository.MessageRepository, elector LeaderElector, batchSize, maxBatches int, log *zap.SugaredLogger) *EncryptionService {
+	return &EncryptionService{
+		repo:       repo,
+		elector:    elector,
+		batchSize:  batchSize,
+		maxBatches: maxBatches,
+		log:        log,
+	}
+}
+
+// Run encrypts batches until one is not full or maxBatches is reached. The
+// rest is left to the next run.
+func (s *EncryptionService) Run(ctx context.Context) error {
+	if s.done.Load() || !s.elector.IsLeader() {
+		return nil
+	}
+
+	total := 0
+	for batches := 0; batches < s.maxBatches; batches++ {
+		n, err := s.repo.EncryptBatch(ctx, s.batchSize)
+		total += n
+		if err != nil {
+			s.log.Errorw("Message encryption failed", "encrypted", total, "error", err)
+			return err
+		}
+
+		if n < s.batchSize {
+			s.done.Store(true)
+			break
+		}
+	}
+
+	if total > 0 || s.done.Load() {
+		s.log.Infow("Message encryption run finished", "encrypted", total, "done", s.done.Load())
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+type fakeEncryptionRepo struct {
+	repository.MessageRepository
+	remaining int
+	batches   int
+}
+
+func (r *fakeEncryptionRepo) EncryptBatch(ctx context.Context, limit int) (int, error) {
+	r.batches++
+	n := min(limit, r.remaining)
+	r.remaining -= n
+	return n, nil
+}
+
+func TestEncryptionServiceRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		leader        bool
+		remaining     int
+		wantBatches   int
+		wantRemaining int
+		// wantSecond is the number of batches of a second run.
+		wantSecond int
+	}{
+		{name: "follower does nothing", leader: false, remaining: 25, wantBatches: 0, wantRemaining: 25, wantSecond: 0},
+		{name: "stops at a partial batch", leader: true, remaining: 25, wantBatches: 3, wantRemaining: 0, wantSecond: 0},
+		{name: "stops at max batches", leader: true, remaining: 100, wantBatches: 5, wantRemaining: 50, wantSecond: 5},
+		{name: "full last batch needs another run", leader: true, remaining: 20, wantBatches: 3, wantRemaining: 0, wantSecond: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeEncryptionRepo{remaining: tt.remaining}
+			svc := service.NewEncryptionService(repo, &fakeElector{leader: tt.leader}, 10, 5, zap.NewNop().Sugar())
+
+			if err := svc.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if repo.batches != tt.wantBatches || repo.remaining != tt.wantRemaining {
+				t.Fatalf("batches = %d, remaining = %d; want %d, %d", repo.batches, repo.remaining, tt.wantBatches, tt.wantRemaining)
+			}
+
+			// Once everything is encrypted later runs skip the database.
+			repo.batches = 0
+			if err := svc.Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if repo.batches != tt.wantSecond {
+				t.Fatalf("second run batches = %d, want %d", repo.batches, tt.wantSecond)
+			}
+		})
+	}
+}
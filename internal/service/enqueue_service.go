@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidMessage      = errors.New("invalid message")
+	ErrRecipientSuppressed = errors.New("recipient is on the suppression list")
+)
+
+// EnqueueRequest describes a message to create. Content is either given
+// directly or rendered from a template with the given variables.
+type EnqueueRequest struct {
+	To         string
+	Content    string
+	TemplateID *int64
+	Variables  map[string]string
+	CampaignID *int64
+	// DedupWindowSeconds overrides the campaign and default deduplication
+	// window for this message. Zero disables deduplication.
+	DedupWindowSeconds *int
+	// TenantID owns the message. When nil, the message belongs to the
+	// tenant of its campaign, or to the default tenant.
+	TenantID *int64
+	// APIKeyID is the key the message is enqueued with, charged next to
+	// the tenant.
+	APIKeyID *int64
+}
+
+// EnqueueService validates new messages and stores them as pending.
+type EnqueueService struct {
+	messages     repository.MessageRepository
+	campaigns    repository.CampaignRepository
+	suppressions repository.SuppressionRepository
+	templates    *TemplateService
+	usage        *UsageService
+	dedupWindow  time.Duration
+	log          *zap.SugaredLogger
+}
+
+func NewEnqueueService(
+	messages repository.MessageRepository,
+	campaigns repository.CampaignRepository,
+	suppressions repository.SuppressionRepository,
+	templates *TemplateService,
+	usage *UsageService,
+	dedupWindow time.Duration,
+	log *zap.SugaredLogger,
+) *EnqueueService {
+	return &EnqueueService{
+		messages:     messages,
+		campaigns:    campaigns,
+		suppressions: suppressions,
+		templates:    templates,
+		usage:        usage,
+		dedupWindow:  dedupWindow,
+		log:          log,
+	}
+}
+
+// Enqueue stores a new pending message and charges it to the quotas of its
+// tenant and API key, which are returned for reporting. It fails with
+// ErrQuotaExceeded when a quota has no room left for the message.
+func (s *EnqueueService) Enqueue(ctx context.Context, req EnqueueRequest) (domain.Message, []domain.QuotaUsage, error) {
+	msg, err := s.prepare(ctx, req)
+	if err != nil {
+		return domain.Message{}, nil, err
+	}
+
+	segments, _ := Segments(msg.Content)
+	charge := domain.UsageCharge{
+		TenantID: msg.TenantID,
+		APIKeyID: req.APIKeyID,
+		Messages: 1,
+		Segments: segments,
+		At:       time.Now(),
+	}
+
+	usages, err := s.usage.Charge(ctx, charge)
+	switch {
+	case errors.Is(err, ErrUnknownTenant):
+		return domain.Message{}, nil, invalid(err)
+	case err != nil:
+		return domain.Message{}, usages, err
+	}
+
+	created, err := s.messages.Create(ctx, msg)
+	if err != nil {
+		s.usage.Release(ctx, charge)
+		if errors.Is(err, repository.ErrInvalidReference) {
+			return created, nil, invalid(ErrUnknownTenant)
+		}
+		return created, nil, err
+	}
+
+	s.log.Infow("Message enqueued", "messageID", created.ID, "tenantID", created.TenantID, "campaignID", created.CampaignID, "templateID", created.TemplateID, "segments", segments)
+
+	return created, usages, nil
+}
+
+// prepare validates req and builds the message to insert.
+func (s *EnqueueService) prepare(ctx context.Context, req EnqueueRequest) (domain.Message, error) {
+	if err := ValidateNumber(req.To); err != nil {
+		return domain.Message{}, invalid(err)
+	}
+
+	suppressed, err := s.suppressions.IsSuppressed(ctx, req.To)
+	if err != nil {
+		return domain.Message{}, err
+	}
+	if suppressed {
+		return domain.Message{}, ErrRecipientSuppressed
+	}
+
+	// The campaign, looked up within the caller's tenant, decides the owner
+	// of the message before anything of that owner is used.
+	tenantID := req.TenantID
+
+	var campaign *domain.Campaign
+	if req.CampaignID != nil {
+		c, err := s.campaigns.Get(ctx, *req.CampaignID, req.TenantID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.Message{}, invalid(ErrCampaignNotFound)
+		}
+		if err != nil {
+			return domain.Message{}, err
+		}
+		campaign = &c
+		tenantID = c.TenantID
+	}
+
+	content := req.Content
+
+	switch {
+	case req.TemplateID != nil && content != "":
+		return domain.Message{}, invalid(errors.New("content and template_id are mutually exclusive"))
+	case req.TemplateID != nil:
+		rendered, err := s.templates.Render(ctx, *req.TemplateID, tenantID, req.Variables)
+		if err != nil {
+			if errors.Is(err, ErrTemplateNotFound) || errors.Is(err, ErrTemplateInvalid) || isContentError(err) {
+				return domain.Message{}, invalid(err)
+			}
+			return domain.Message{}, err
+		}
+		content = rendered
+	default:
+		if err := ValidateContent(content); err != nil {
+			return domain.Message{}, invalid(err)
+		}
+	}
+
+	if req.DedupWindowSeconds != nil && *req.DedupWindowSeconds < 0 {
+		return domain.Message{}, invalid(ErrInvalidDedupWindow)
+	}
+
+	hash := ContentHash(content)
+
+	return domain.Message{
+		To:                 req.To,
+		Content:            content,
+		CampaignID:         req.CampaignID,
+		TemplateID:         req.TemplateID,
+		ContentHash:        &hash,
+		DedupWindowSeconds: s.resolveDedupWindow(req, campaign),
+		TenantID:           tenantID,
+	}, nil
+}
+
+// resolveDedupWindow picks the deduplication window of a message: the
+// request override first, then the campaign setting, then the default.
+func (s *EnqueueService) resolveDedupWindow(req EnqueueRequest, campaign *domain.Campaign) *int {
+	window := int(s.dedupWindow.Seconds())
+
+	switch {
+	case req.DedupWindowSeconds != nil:
+		window = *req.DedupWindowSeconds
+	case campaign != nil && campaign.DedupWindowSeconds != nil:
+		window = *campaign.DedupWindowSeconds
+	}
+
+	if window <= 0 {
+		return nil
+	}
+
+	return &window
+}
+
+func invalid(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+}
+
+func isContentError(err error) bool {
+	return errors.Is(err, ErrContentEmpty) || errors.Is(err, ErrContentTooLong) || errors.Is(err, ErrContentEncoding)
+}
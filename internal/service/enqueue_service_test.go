@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+// inTenant mirrors the tenant condition of the Postgres repositories: a nil
+// tenantID sees every tenant.
+func inTenant(owner, tenantID *int64) bool {
+	return tenantID == nil || (owner != nil && *owner == *tenantID)
+}
+
+type fakeCampaignRepo struct {
+	repository.CampaignRepository
+	campaigns map[int64]domain.Campaign
+}
+
+func (r *fakeCampaignRepo) Get(ctx context.Context, id int64, tenantID *int64) (domain.Campaign, error) {
+	c, ok := r.campaigns[id]
+	if !ok || !inTenant(c.TenantID, tenantID) {
+		return domain.Campaign{}, repository.ErrNotFound
+	}
+	return c, nil
+}
+
+type fakeTemplateRepo struct {
+	repository.TemplateRepository
+	templates map[int64]domain.Template
+}
+
+func (r *fakeTemplateRepo) Get(ctx context.Context, id int64, tenantID *int64) (domain.Template, error) {
+	t, ok := r.templates[id]
+	if !ok || !inTenant(t.TenantID, tenantID) {
+		return domain.Template{}, repository.ErrNotFound
+	}
+	return t, nil
+}
+
+type fakeUsageRepo struct {
+	repository.UsageRepository
+}
+
+func (r *fakeUsageRepo) Consume(ctx context.Context, charge domain.UsageCharge) ([]domain.QuotaUsage, error) {
+	return nil, nil
+}
+
+type fakeEnqueueRepo struct {
+	repository.MessageRepository
+}
+
+func (r *fakeEnqueueRepo) Create(ctx context.Context, msg domain.Message) (domain.Message, error) {
+	msg.ID = 1
+	return msg, nil
+}
+
+func TestEnqueueTemplateTenant(t *testing.T) {
+	ptr := func(n int64) *int64 { return &n }
+
+	campaigns := &fakeCampaignRepo{campaigns: map[int64]domain.Campaign{
+		1: {ID: 1, TenantID: ptr(5)},
+	}}
+	templates := &fakeTemplateRepo{templates: map[int64]domain.Template{
+		10: {ID: 10, TenantID: ptr(3), Body: "Tenant 3 says {{.code}}"},
+		11: {ID: 11, TenantID: ptr(5), Body: "Tenant 5 says {{.code}}"},
+	}}
+
+	tests := []struct {
+		name           string
+		req            service.EnqueueRequest
+		expectedTenant *int64
+		expectedErr    error
+	}{
+		{
+			name:           "Campaign_Tenant_Template",
+			req:            service.EnqueueRequest{CampaignID: ptr(1), TemplateID: ptr(11)},
+			expectedTenant: ptr(5),
+		},
+		{
+			name:        "Other_Tenant_Template_In_Campaign",
+			req:         service.EnqueueRequest{CampaignID: ptr(1), TemplateID: ptr(10)},
+			expectedErr: service.ErrTemplateNotFound,
+		},
+		{
+			name:        "Other_Tenant_Template_Without_Campaign",
+			req:         service.EnqueueRequest{TemplateID: ptr(10)},
+			expectedErr: service.ErrTemplateNotFound,
+		},
+		{
+			name:        "Campaign_Outside_Caller_Tenant",
+			req:         service.EnqueueRequest{CampaignID: ptr(1), TemplateID: ptr(10), TenantID: ptr(3)},
+			expectedErr: service.ErrCampaignNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := zap.NewNop().Sugar()
+			svc := service.NewEnqueueService(
+				&fakeEnqueueRepo{},
+				campaigns,
+				&fakeSuppressionRepo{},
+				service.NewTemplateService(templates, log),
+				service.NewUsageService(&fakeUsageRepo{}, log),
+				0,
+				log,
+			)
+
+			tt.req.To = "+905551234567"
+			tt.req.Variables = map[string]string{"code": "1234"}
+
+			msg, _, err := svc.Enqueue(context.Background(), tt.req)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Enqueue() error = %v, expected %v", err, tt.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if msg.TenantID == nil || *msg.TenantID != *tt.expectedTenant {
+				t.Errorf("TenantID = %v, expected %d", msg.TenantID, *tt.expectedTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+type FrequencyCapPolicy string
+
+const (
+	// FrequencyCapDefer postpones an over-cap message until the window frees up.
+	FrequencyCapDefer FrequencyCapPolicy = "defer"
+	// FrequencyCapFail fails an over-cap message.
+	FrequencyCapFail FrequencyCapPolicy = "fail"
+)
+
+// FrequencyCapReason is recorded as last_error of capped messages.
+const FrequencyCapReason = "frequency_capped"
+
+// FrequencyCap limits how many messages a recipient receives within a
+// rolling window. A zero MaxMessages disables the cap.
+type FrequencyCap struct {
+	MaxMessages int
+	Window      time.Duration
+	Policy      FrequencyCapPolicy
+}
+
+func (c FrequencyCap) Enabled() bool {
+	return c.MaxMessages > 0 && c.Window > 0
+}
+
+func (c FrequencyCap) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	switch c.Policy {
+	case FrequencyCapDefer, FrequencyCapFail:
+		return nil
+	default:
+		return fmt.Errorf("unknown frequency cap policy %q", c.Policy)
+	}
+}
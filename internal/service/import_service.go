@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidImport  = errors.New("invalid import")
+	ErrImportNotFound = errors.New("import not found")
+)
+
+const (
+	// maxImportRows bounds a single upload, which is held in memory while
+	// it is imported.
+	maxImportRows = 100_000
+	// importProgressEvery is how many rows are imported between progress
+	// updates.
+	importProgressEvery = 100
+	// importFinishTimeout bounds recording the outcome of an import that
+	// was interrupted by a shutdown.
+	importFinishTimeout = 5 * time.Second
+)
+
+// ImportRow is a data row of an upload and the line it starts on.
+type ImportRow struct {
+	Line   int
+	Record []string
+}
+
+// ImportFile is a parsed upload. Rows with a different number of fields than
+// Columns are kept and rejected when they are imported.
+type ImportFile struct {
+	Columns []string
+	Rows    []ImportRow
+}
+
+// ParseImport reads a CSV upload. It needs a "to" column, and a "content"
+// column unless the messages are rendered from a template, in which case
+// every other column is a template variable.
+func ParseImport(r io.Reader, withTemplate bool) (ImportFile, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return ImportFile{}, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return ImportFile{}, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+
+	file := ImportFile{Columns: make([]string, 0, len(header))}
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		if i == 0 {
+			// Spreadsheet applications like to start files with a BOM.
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return ImportFile{}, fmt.Errorf("%w: column %d has no name", ErrInvalidImport, i+1)
+		}
+		if seen[column] {
+			return ImportFile{}, fmt.Errorf("%w: duplicate column %q", ErrInvalidImport, column)
+		}
+		seen[column] = true
+		file.Columns = append(file.Columns, column)
+	}
+
+	switch {
+	case !seen["to"]:
+		return ImportFile{}, fmt.Errorf("%w: a to column is required", ErrInvalidImport)
+	case withTemplate && seen["content"]:
+		return ImportFile{}, fmt.Errorf("%w: a content column cannot be combined with template_id", ErrInvalidImport)
+	case !withTemplate && !seen["content"]:
+		return ImportFile{}, fmt.Errorf("%w: a content column or template_id is required", ErrInvalidImport)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ImportFile{}, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+		if len(file.Rows) == maxImportRows {
+			return ImportFile{}, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+		line, _ := reader.FieldPos(0)
+		file.Rows = append(file.Rows, ImportRow{Line: line, Record: record})
+	}
+
+	if len(file.Rows) == 0 {
+		return ImportFile{}, fmt.Errorf("%w: file has no rows", ErrInvalidImport)
+	}
+
+	return file, nil
+}
+
+// ImportRequest is an upload to enqueue. TemplateID, CampaignID, TenantID
+// and APIKeyID apply to every row as they do to a single message.
+type ImportRequest struct {
+	Filename   string
+	File       io.Reader
+	TemplateID *int64
+	CampaignID *int64
+	TenantID   *int64
+	APIKeyID   *int64
+	Actor      string
+}
+
+// ImportService enqueues uploaded recipient lists in the background, one
+// goroutine per import.
+type ImportService struct {
+	repo    repository.ImportRepository
+	enqueue *EnqueueService
+	log     *zap.SugaredLogger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewImportService(repo repository.ImportRepository, enqueue *EnqueueService, log *zap.SugaredLogger) *ImportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ImportService{
+		repo:    repo,
+		enqueue: enqueue,
+		log:     log,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start validates an upload, records the import and enqueues its rows in
+// the background. The returned import is still running.
+func (s *ImportService) Start(ctx context.Context, req ImportRequest) (domain.Import, error) {
+	file, err := ParseImport(req.File, req.TemplateID != nil)
+	if err != nil {
+		return domain.Import{}, err
+	}
+
+	imp, err := s.repo.Create(ctx, domain.Import{
+		Filename:   req.Filename,
+		Columns:    file.Columns,
+		TemplateID: req.TemplateID,
+		CampaignID: req.CampaignID,
+		TenantID:   req.TenantID,
+		CreatedBy:  req.Actor,
+		TotalRows:  len(file.Rows),
+	})
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return imp, fmt.Errorf("%w: unknown template, campaign or tenant", ErrInvalidImport)
+	}
+	if err != nil {
+		return imp, err
+	}
+
+	s.log.Infow("Import started", "importID", imp.ID, "rows", imp.TotalRows, "by", req.Actor)
+
+	s.wg.Add(1)
+	go s.run(imp, file, req)
+
+	return imp, nil
+}
+
+func (s *ImportService) run(imp domain.Import, file ImportFile, req ImportRequest) {
+	defer s.wg.Done()
+
+	var progress domain.ImportProgress
+
+	for _, row := range file.Rows {
+		if err := s.ctx.Err(); err != nil {
+			s.finish(imp.ID, progress, fmt.Errorf("interrupted by shutdown"))
+			return
+		}
+
+		reason, err := s.importRow(file.Columns, row, req)
+		if err != nil {
+			s.finish(imp.ID, progress, err)
+			return
+		}
+
+		progress.Processed++
+		if reason == "" {
+			progress.Accepted++
+		} else {
+			progress.Rejected++
+			progress.Errors = append(progress.Errors, domain.ImportRowError{Line: row.Line, Record: row.Record, Reason: reason})
+		}
+
+		if progress.Processed%importProgressEvery == 0 {
+			if err := s.repo.Progress(s.ctx, imp.ID, progress); err != nil {
+				s.finish(imp.ID, progress, err)
+				return
+			}
+			progress.Errors = nil
+		}
+	}
+
+	s.finish(imp.ID, progress, nil)
+}
+
+// importRow enqueues a row. It returns why the row was rejected, or an error
+// when the import cannot go on.
+func (s *ImportService) importRow(columns []string, row ImportRow, req ImportRequest) (string, error) {
+	if len(row.Record) != len(columns) {
+		return fmt.Sprintf("expected %d fields, got %d", len(columns), len(row.Record)), nil
+	}
+
+	enqueue := EnqueueRequest{
+		TemplateID: req.TemplateID,
+		CampaignID: req.CampaignID,
+		TenantID:   req.TenantID,
+		APIKeyID:   req.APIKeyID,
+	}
+	if req.TemplateID != nil {
+		enqueue.Variables = make(map[string]string, len(columns)-1)
+	}
+	for i, column := range columns {
+		value := strings.TrimSpace(row.Record[i])
+		switch {
+		case column == "to":
+			enqueue.To = value
+		case column == "content":
+			enqueue.Content = row.Record[i]
+		case enqueue.Variables != nil:
+			enqueue.Variables[column] = value
+		}
+	}
+
+	_, _, err := s.enqueue.Enqueue(s.ctx, enqueue)
+	switch {
+	case errors.Is(err, ErrInvalidMessage), errors.Is(err, ErrRecipientSuppressed), errors.Is(err, ErrQuotaExceeded):
+		return err.Error(), nil
+	case err != nil:
+		return "", err
+	}
+	return "", nil
+}
+
+// finish stores the last progress of an import and its outcome. A non-nil
+// cause fails the import.
+func (s *ImportService) finish(id int64, progress domain.ImportProgress, cause error) {
+	// Outlive a shutdown long enough to record why the import stopped.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(s.ctx), importFinishTimeout)
+	defer cancel()
+
+	if err := s.repo.Progress(ctx, id, progress); err != nil {
+		s.log.Errorw("Unable to store import progress", "importID", id, "error", err)
+	}
+
+	status := domain.ImportCompleted
+	var reason *string
+	if cause != nil {
+		status = domain.ImportFailed
+		msg := cause.Error()
+		reason = &msg
+		s.log.Errorw("Import failed", "importID", id, "processed", progress.Processed, "error", cause)
+	}
+
+	if err := s.repo.Finish(ctx, id, status, reason); err != nil {
+		s.log.Errorw("Unable to finish import", "importID", id, "error", err)
+		return
+	}
+
+	s.log.Infow("Import finished", "importID", id, "status", status, "accepted", progress.Accepted, "rejected", progress.Rejected)
+}
+
+func (s *ImportService) Get(ctx context.Context, id int64, tenantID *int64) (domain.Import, error) {
+	imp, err := s.repo.Get(ctx, id, tenantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return imp, ErrImportNotFound
+	}
+	return imp, err
+}
+
+// Errors returns an import with its rejected rows so far.
+func (s *ImportService) Errors(ctx context.Context, id int64, tenantID *int64) (domain.Import, []domain.ImportRowError, error) {
+	imp, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return imp, nil, err
+	}
+
+	errs, err := s.repo.Errors(ctx, id)
+	return imp, errs, err
+}
+
+// Shutdown stops running imports and waits until they have recorded how far
+// they got.
+func (s *ImportService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/service"
+)
+
+func TestParseImport(t *testing.T) {
+	file, err := service.ParseImport(strings.NewReader("\ufeffto, content\n+905551111111,Hello\n\n+905552222222,\"Two\nlines\"\n+905553333333\n"), false)
+	if err != nil {
+		t.Fatalf("ParseImport() error = %v", err)
+	}
+
+	if !slices.Equal(file.Columns, []string{"to", "content"}) {
+		t.Errorf("columns = %q", file.Columns)
+	}
+	if len(file.Rows) != 3 {
+		t.Fatalf("got %d rows, expected 3", len(file.Rows))
+	}
+
+	lines := []int{file.Rows[0].Line, file.Rows[1].Line, file.Rows[2].Line}
+	if !slices.Equal(lines, []int{2, 4, 6}) {
+		t.Errorf("lines = %v, expected [2 4 6]", lines)
+	}
+	if len(file.Rows[2].Record) != 1 {
+		t.Errorf("short row = %q, expected it to be kept as is", file.Rows[2].Record)
+	}
+}
+
+func TestParseImportRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		csv          string
+		withTemplate bool
+	}{
+		{"empty", "", false},
+		{"no rows", "to,content\n", false},
+		{"no to column", "number,content\n1,Hi\n", false},
+		{"no content column", "to,name\n+905551111111,Ada\n", false},
+		{"content with template", "to,content\n+905551111111,Hi\n", true},
+		{"duplicate column", "to,name,name\n+905551111111,Ada,Ada\n", true},
+		{"unnamed column", "to,\n+905551111111,Ada\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ParseImport(strings.NewReader(tt.csv), tt.withTemplate); !errors.Is(err, service.ErrInvalidImport) {
+				t.Errorf("ParseImport() error = %v, expected ErrInvalidImport", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidInbound = errors.New("from and content are required")
+
+// optOutKeywords are the replies that unsubscribe a recipient.
+var optOutKeywords = map[string]bool{
+	"STOP":        true,
+	"STOPALL":     true,
+	"UNSUBSCRIBE": true,
+	"CANCEL":      true,
+	"END":         true,
+	"QUIT":        true,
+}
+
+// InboundRequest is a reply delivered by the provider.
+type InboundRequest struct {
+	From       string
+	To         string
+	Content    string
+	ExternalID string
+	ReceivedAt *time.Time
+}
+
+type InboundService struct {
+	repo         repository.InboundMessageRepository
+	suppressions repository.SuppressionRepository
+	log          *zap.SugaredLogger
+}
+
+func NewInboundService(repo repository.InboundMessageRepository, suppressions repository.SuppressionRepository, log *zap.SugaredLogger) *InboundService {
+	return &InboundService{
+		repo:         repo,
+		suppressions: suppressions,
+		log:          log,
+	}
+}
+
+// Receive stores an inbound message and suppresses the sender when the reply
+// is an opt-out keyword. The sender must normalize to an E.164 number.
+func (s *InboundService) Receive(ctx context.Context, req InboundRequest) (domain.InboundMessage, error) {
+	from := NormalizeNumber(req.From)
+	if from == "" || req.Content == "" {
+		return domain.InboundMessage{}, ErrInvalidInbound
+	}
+	if err := ValidateNumber(from); err != nil {
+		return domain.InboundMessage{}, fmt.Errorf("invalid from: %w", err)
+	}
+
+	msg := domain.InboundMessage{
+		From:       from,
+		To:         optional(req.To),
+		Content:    req.Content,
+		ExternalID: optional(req.ExternalID),
+		OptOut:     IsOptOut(req.Content),
+		ReceivedAt: time.Now().UTC(),
+	}
+	if req.ReceivedAt != nil {
+		msg.ReceivedAt = req.ReceivedAt.UTC()
+	}
+
+	created, err := s.repo.Create(ctx, msg)
+	if err != nil {
+		return created, err
+	}
+
+	s.log.Infow("Inbound message received", "inboundID", created.ID, "messageID", created.MessageID, "optOut", created.OptOut)
+
+	if !created.OptOut {
+		return created, nil
+	}
+
+	added, err := s.suppressions.CreateIfMissing(ctx, domain.Suppression{
+		Number: from,
+		Reason: "replied " + firstWord(req.Content),
+		Source: domain.SuppressionSourceInbound,
+	})
+	if err != nil {
+		return created, err
+	}
+	if added {
+		s.log.Infow("Recipient opted out", "inboundID", created.ID)
+	}
+
+	return created, nil
+}
+
+func (s *InboundService) List(ctx context.Context, from string, tenantID *int64, limit, offset int) ([]domain.InboundMessage, error) {
+	return s.repo.List(ctx, from, tenantID, limit, offset)
+}
+
+// IsOptOut reports whether content starts with an opt-out keyword, ignoring
+// case and punctuation.
+func IsOptOut(content string) bool {
+	return optOutKeywords[firstWord(content)]
+}
+
+func firstWord(content string) string {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	word := strings.TrimFunc(fields[0], func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	return strings.ToUpper(word)
+}
+
+func optional(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+type fakeInboundRepo struct {
+	repository.InboundMessageRepository
+}
+
+func (r *fakeInboundRepo) Create(ctx context.Context, msg domain.InboundMessage) (domain.InboundMessage, error) {
+	msg.ID = 1
+	return msg, nil
+}
+
+type fakeSuppressionRepo struct {
+	repository.SuppressionRepository
+	added []string
+}
+
+func (r *fakeSuppressionRepo) CreateIfMissing(ctx context.Context, s domain.Suppression) (bool, error) {
+	r.added = append(r.added, s.Number)
+	return true, nil
+}
+
+func (r *fakeSuppressionRepo) IsSuppressed(ctx context.Context, number string) (bool, error) {
+	return false, nil
+}
+
+func TestIsOptOut(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{"STOP", true},
+		{"stop", true},
+		{"  Stop.  ", true},
+		{"STOP sending me these", true},
+		{"unsubscribe", true},
+		{"Quit!", true},
+		{"Please stop", false},
+		{"Stopwatch", false},
+		{"Thanks!", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := service.IsOptOut(tt.content); got != tt.expected {
+			t.Errorf("IsOptOut(%q) = %v, expected %v", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestReceive(t *testing.T) {
+	tests := []struct {
+		name           string
+		from           string
+		content        string
+		expectedNumber string
+		expectedErr    error
+	}{
+		{name: "E164_Opt_Out", from: "+905551234567", content: "STOP", expectedNumber: "+905551234567"},
+		{name: "Bare_Digits", from: "905551234567", content: "stop", expectedNumber: "+905551234567"},
+		{name: "International_Prefix", from: "00 90 555 123-45-67", content: "STOP", expectedNumber: "+905551234567"},
+		{name: "Not_Opt_Out", from: "+905551234567", content: "Thanks!"},
+		{name: "Alphanumeric", from: "INSIDER", content: "STOP", expectedErr: service.ErrInvalidNumber},
+		{name: "Too_Short", from: "+12345", content: "STOP", expectedErr: service.ErrInvalidNumber},
+		{name: "Missing_From", from: " ", content: "STOP", expectedErr: service.ErrInvalidInbound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions := &fakeSuppressionRepo{}
+			svc := service.NewInboundService(&fakeInboundRepo{}, suppressions, zap.NewNop().Sugar())
+
+			_, err := svc.Receive(context.Background(), service.InboundRequest{From: tt.from, Content: tt.content})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Receive() error = %v, expected %v", err, tt.expectedErr)
+			}
+
+			var expected []string
+			if tt.expectedNumber != "" {
+				expected = []string{tt.expectedNumber}
+			}
+			if !slices.Equal(suppressions.added, expected) {
+				t.Errorf("suppressed %v, expected %v", suppressions.added, expected)
+			}
+		})
+	}
+}
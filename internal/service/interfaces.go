@@ -9,3 +9,9 @@ type SendResponse struct {
 type Sender interface {
 	Send(ctx context.Context, to, content string) (*SendResponse, error)
 }
+
+// SenderRegistry resolves the sender to use for a tenant's messages and the
+// name of the provider behind it.
+type SenderRegistry interface {
+	SenderFor(ctx context.Context, tenantID *int64) (Sender, string, error)
+}
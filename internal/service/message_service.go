@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/LevanPro/insider/internal/domain"
@@ -12,43 +14,128 @@ import (
 )
 
 var (
-	ErrGetMessageFail = errors.New("failed to get unsent messages")
+	ErrGetMessageFail        = errors.New("failed to get unsent messages")
+	ErrNothingToRequeue      = errors.New("either ids or a non-empty filter must be provided")
+	ErrNothingToCancel       = errors.New("either ids or a non-empty filter must be provided")
+	ErrMessageNotFound       = errors.New("message not found")
+	ErrMessageNotCancellable = errors.New("only pending messages can be cancelled")
 )
 
+// ProcessResult summarises a single processing cycle.
+type ProcessResult struct {
+	Fetched    int `json:"fetched"`
+	Sent       int `json:"sent"`
+	Failed     int `json:"failed"`
+	Skipped    int `json:"skipped"`
+	Suppressed int `json:"suppressed"`
+	Deferred   int `json:"deferred"`
+	Duplicate  int `json:"duplicate"`
+}
+
+// markSentAttempts and markSentBackoff bound the retries of recording a sent
+// message; the backoff grows with each attempt.
+const (
+	markSentAttempts = 4
+	markSentBackoff  = 100 * time.Millisecond
+)
+
+type outcome int
+
+const (
+	outcomeSent outcome = iota
+	outcomeFailed
+	outcomeSkipped
+	outcomeSuppressed
+	outcomeDeferred
+	outcomeDuplicate
+)
+
+type processCounters struct {
+	sent       atomic.Int64
+	failed     atomic.Int64
+	skipped    atomic.Int64
+	suppressed atomic.Int64
+	deferred   atomic.Int64
+	duplicate  atomic.Int64
+}
+
+func (c *processCounters) add(o outcome) {
+	switch o {
+	case outcomeSent:
+		c.sent.Add(1)
+	case outcomeFailed:
+		c.failed.Add(1)
+	case outcomeSkipped:
+		c.skipped.Add(1)
+	case outcomeSuppressed:
+		c.suppressed.Add(1)
+	case outcomeDeferred:
+		c.deferred.Add(1)
+	case outcomeDuplicate:
+		c.duplicate.Add(1)
+	}
+}
+
 type MessageService struct {
-	repo       repository.MessageRepository
-	sender     Sender
-	batchSize  int
-	numWorkers int
-	log        *zap.SugaredLogger
+	repo         repository.MessageRepository
+	suppressions repository.SuppressionRepository
+	senders      SenderRegistry
+	batchSize    int
+	numWorkers   int
+	frequencyCap FrequencyCap
+	prices       *PriceTable
+	log          *zap.SugaredLogger
 }
 
-func NewMessageService(repo repository.MessageRepository, sender Sender, batchSize int, numWorkers int, log *zap.SugaredLogger) *MessageService {
+func NewMessageService(
+	repo repository.MessageRepository,
+	suppressions repository.SuppressionRepository,
+	senders SenderRegistry,
+	batchSize int,
+	numWorkers int,
+	frequencyCap FrequencyCap,
+	prices *PriceTable,
+	log *zap.SugaredLogger,
+) *MessageService {
 	if numWorkers <= 0 {
 		numWorkers = 1
 	}
 	return &MessageService{
-		repo:       repo,
-		sender:     sender,
-		batchSize:  batchSize,
-		numWorkers: numWorkers,
-		log:        log,
+		repo:         repo,
+		suppressions: suppressions,
+		senders:      senders,
+		batchSize:    batchSize,
+		numWorkers:   numWorkers,
+		frequencyCap: frequencyCap,
+		prices:       prices,
+		log:          log,
 	}
 }
 
 func (s *MessageService) ProcessNextUnsent(ctx context.Context) error {
-	s.log.Infow("Starting processing")
+	_, err := s.ProcessBatch(ctx, s.batchSize)
+	return err
+}
+
+// ProcessBatch runs one processing cycle for up to batchSize messages and
+// reports how each of them ended up.
+func (s *MessageService) ProcessBatch(ctx context.Context, batchSize int) (ProcessResult, error) {
+	s.log.Infow("Starting processing", "batchSize", batchSize)
 	defer s.log.Infow("End processing")
 
-	msgs, err := s.repo.GetNextUnsent(ctx, s.batchSize)
+	var result ProcessResult
+
+	msgs, err := s.repo.GetNextUnsent(ctx, batchSize)
 	if err != nil {
-		s.log.Errorw("ProcessNextUnsent", "ERROR", err)
-		return ErrGetMessageFail
+		s.log.Errorw("ProcessBatch", "ERROR", err)
+		return result, ErrGetMessageFail
 	}
 
+	result.Fetched = len(msgs)
+
 	if len(msgs) == 0 {
 		s.log.Infow("No messages to process")
-		return nil
+		return result, nil
 	}
 
 	s.log.Infow("Processing messages", "count", len(msgs))
@@ -56,13 +143,14 @@ func (s *MessageService) ProcessNextUnsent(ctx context.Context) error {
 	msgChan := make(chan domain.Message, len(msgs))
 
 	var wg sync.WaitGroup
+	var counters processCounters
 
 	workerCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	for i := 0; i < s.numWorkers; i++ {
 		wg.Add(1)
-		go s.worker(workerCtx, i, msgChan, &wg)
+		go s.worker(workerCtx, i, msgChan, &wg, &counters)
 	}
 
 	for _, msg := range msgs {
@@ -73,7 +161,7 @@ func (s *MessageService) ProcessNextUnsent(ctx context.Context) error {
 			close(msgChan)
 			cancel()
 			wg.Wait()
-			return ctx.Err()
+			return counters.result(result.Fetched), ctx.Err()
 		}
 	}
 
@@ -81,10 +169,22 @@ func (s *MessageService) ProcessNextUnsent(ctx context.Context) error {
 
 	wg.Wait()
 
-	return nil
+	return counters.result(result.Fetched), nil
 }
 
-func (s *MessageService) worker(ctx context.Context, workerID int, msgChan <-chan domain.Message, wg *sync.WaitGroup) {
+func (c *processCounters) result(fetched int) ProcessResult {
+	return ProcessResult{
+		Fetched:    fetched,
+		Sent:       int(c.sent.Load()),
+		Failed:     int(c.failed.Load()),
+		Skipped:    int(c.skipped.Load()),
+		Suppressed: int(c.suppressed.Load()),
+		Deferred:   int(c.deferred.Load()),
+		Duplicate:  int(c.duplicate.Load()),
+	}
+}
+
+func (s *MessageService) worker(ctx context.Context, workerID int, msgChan <-chan domain.Message, wg *sync.WaitGroup, counters *processCounters) {
 	defer wg.Done()
 
 	s.log.Infow("Worker started", "workerID", workerID)
@@ -97,7 +197,7 @@ func (s *MessageService) worker(ctx context.Context, workerID int, msgChan <-cha
 				return
 			}
 
-			s.processMessage(ctx, workerID, msg)
+			counters.add(s.processMessage(ctx, workerID, msg))
 
 		case <-ctx.Done():
 			s.log.Warnw("Worker ctx cancelled", "workerID", workerID)
@@ -106,36 +206,284 @@ func (s *MessageService) worker(ctx context.Context, workerID int, msgChan <-cha
 	}
 }
 
-func (s *MessageService) processMessage(ctx context.Context, workerID int, msg domain.Message) {
+func (s *MessageService) processMessage(ctx context.Context, workerID int, msg domain.Message) outcome {
 	s.log.Infow("Processing message",
 		"workerID", workerID,
 		"messageID", msg.ID,
 		"to", msg.To)
 
 	// TODO:: need to think of what do to with such messages also if phone number is not in correct format
-	if len(msg.Content) > 160 {
-		s.log.Warnw("message content exceeds 160 characters", "workerID", workerID, "messageID", msg.ID, "length", len(msg.Content))
-		return
+	if err := ValidateContent(msg.Content); err != nil {
+		s.log.Warnw("Message content is not valid", "workerID", workerID, "messageID", msg.ID, "error", err)
+		if err := s.repo.MarkAsFailed(ctx, msg.ID, err.Error()); err != nil {
+			s.log.Errorw("Unable to mark message as failed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeFailed
+	}
+
+	// The recipient may have opted out after the message was enqueued.
+	suppressed, err := s.suppressions.IsSuppressed(ctx, msg.To)
+	if err != nil {
+		s.log.Errorw("Unable to check suppression list", "workerID", workerID, "messageID", msg.ID, "error", err)
+		if err := s.repo.MarkAsFailed(ctx, msg.ID, "suppression check failed"); err != nil {
+			s.log.Errorw("Unable to mark message as failed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeFailed
+	}
+	if suppressed {
+		s.log.Infow("Recipient is suppressed, message not sent", "workerID", workerID, "messageID", msg.ID)
+		if err := s.repo.MarkAsSuppressed(ctx, msg.ID, "recipient is on the suppression list"); err != nil {
+			s.log.Errorw("Unable to mark message as suppressed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeSuppressed
+	}
+
+	if s.isDuplicate(ctx, workerID, msg) {
+		return outcomeDuplicate
+	}
+
+	if o, capped := s.applyFrequencyCap(ctx, workerID, msg); capped {
+		return o
+	}
+
+	sender, provider, err := s.senders.SenderFor(ctx, msg.TenantID)
+	if err != nil {
+		s.log.Errorw("Unable to resolve tenant sender", "workerID", workerID, "messageID", msg.ID, "tenantID", msg.TenantID, "error", err)
+		if err := s.repo.MarkAsFailed(ctx, msg.ID, "tenant sender unavailable"); err != nil {
+			s.log.Errorw("Unable to mark message as failed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeFailed
 	}
 
 	// TODO:: implement retry logic
-	resp, err := s.sender.Send(ctx, msg.To, msg.Content)
+	resp, err := sender.Send(ctx, msg.To, msg.Content)
 	if err != nil {
 		s.log.Errorw("Failed to send message", "workerID", workerID, "messageID", msg.ID, "error", err)
-		s.repo.MarkAsFailed(ctx, msg.ID)
-		return
+		if err := s.repo.MarkAsFailed(ctx, msg.ID, err.Error()); err != nil {
+			s.log.Errorw("Unable to mark message as failed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeFailed
 	}
 
 	now := time.Now().UTC()
 	extID := resp.MessageID
-	if err := s.repo.MarkAsSent(ctx, msg.ID, now, &extID); err != nil {
+	cost := s.cost(workerID, msg, provider)
+	if err := s.markAsSent(ctx, workerID, msg.ID, now, extID, cost); err != nil {
 		s.log.Errorw("Unable to mark message as sent", "workerID", workerID, "messageID", msg.ID, "externalID", extID, "error", err)
-		return
+		return outcomeSent
 	}
 
 	s.log.Infow("Message has been sent successfully", "workerID", workerID, "messageID", msg.ID, "externalID", extID)
+	return outcomeSent
+}
+
+// markAsSent records a message the provider accepted. It retries, as a
+// message left in processing is claimed again once stale and sent twice.
+func (s *MessageService) markAsSent(ctx context.Context, workerID int, id int64, sentAt time.Time, externalID string, cost domain.MessageCost) error {
+	// The message is out, so record it even when the batch is cancelled.
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	for attempt := 1; attempt <= markSentAttempts; attempt++ {
+		if err = s.repo.MarkAsSent(ctx, id, sentAt, &externalID, cost); err == nil {
+			return nil
+		}
+		if attempt < markSentAttempts {
+			s.log.Warnw("Retrying to mark message as sent", "workerID", workerID, "messageID", id, "attempt", attempt, "error", err)
+			time.Sleep(time.Duration(attempt) * markSentBackoff)
+		}
+	}
+	return err
+}
+
+// cost prices msg by the segments it was sent in. Messages without a price
+// are still recorded, so they show up as unpriced in cost reports.
+func (s *MessageService) cost(workerID int, msg domain.Message, provider string) domain.MessageCost {
+	segments, _ := Segments(msg.Content)
+	cost := domain.MessageCost{Provider: provider, Segments: segments}
+
+	price, ok := s.prices.Lookup(provider, msg.To)
+	if !ok {
+		s.log.Warnw("No price for message", "workerID", workerID, "messageID", msg.ID, "provider", provider)
+		return cost
+	}
+
+	cost.UnitPrice = &price
+	return cost
 }
 
-func (s *MessageService) ListSent(ctx context.Context, limit, offset int) ([]domain.Message, error) {
-	return s.repo.ListSent(ctx, limit, offset)
+// isDuplicate marks msg as duplicate when an earlier message with the same
+// recipient and content was created or sent within its dedup window.
+func (s *MessageService) isDuplicate(ctx context.Context, workerID int, msg domain.Message) bool {
+	if msg.DedupWindowSeconds == nil || msg.ContentHash == nil {
+		return false
+	}
+
+	window := time.Duration(*msg.DedupWindowSeconds) * time.Second
+
+	originalID, err := s.repo.FindDuplicate(ctx, msg, window)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		// Do not block delivery on a failed check.
+		s.log.Errorw("Unable to check for duplicates", "workerID", workerID, "messageID", msg.ID, "error", err)
+		return false
+	}
+
+	s.log.Infow("Duplicate message not sent", "workerID", workerID, "messageID", msg.ID, "duplicateOf", originalID)
+	if err := s.repo.MarkAsDuplicate(ctx, msg.ID, originalID); err != nil {
+		s.log.Errorw("Unable to mark message as duplicate", "workerID", workerID, "messageID", msg.ID, "error", err)
+	}
+
+	return true
+}
+
+// applyFrequencyCap defers or fails msg when its recipient already received
+// the maximum number of messages within the cap window. Otherwise msg holds a
+// reservation, so concurrent workers cannot send past the cap.
+func (s *MessageService) applyFrequencyCap(ctx context.Context, workerID int, msg domain.Message) (outcome, bool) {
+	if !s.frequencyCap.Enabled() {
+		return 0, false
+	}
+
+	since := time.Now().UTC().Add(-s.frequencyCap.Window)
+
+	reserved, count, oldest, err := s.repo.ReserveSend(ctx, msg.ID, msg.To, since, s.frequencyCap.MaxMessages)
+	if err != nil {
+		// Do not block delivery on a failed check.
+		s.log.Errorw("Unable to check frequency cap", "workerID", workerID, "messageID", msg.ID, "error", err)
+		return 0, false
+	}
+
+	if reserved {
+		return 0, false
+	}
+
+	if s.frequencyCap.Policy == FrequencyCapFail {
+		s.log.Warnw("Frequency cap reached, message failed", "workerID", workerID, "messageID", msg.ID, "sentInWindow", count)
+		if err := s.repo.MarkAsFailed(ctx, msg.ID, FrequencyCapReason); err != nil {
+			s.log.Errorw("Unable to mark message as failed", "workerID", workerID, "messageID", msg.ID, "error", err)
+		}
+		return outcomeFailed, true
+	}
+
+	// The window frees up once the oldest send in it expires.
+	notBefore := oldest.Add(s.frequencyCap.Window)
+
+	s.log.Infow("Frequency cap reached, message deferred", "workerID", workerID, "messageID", msg.ID, "sentInWindow", count, "notBefore", notBefore)
+	if err := s.repo.Defer(ctx, msg.ID, notBefore, FrequencyCapReason); err != nil {
+		s.log.Errorw("Unable to defer message", "workerID", workerID, "messageID", msg.ID, "error", err)
+	}
+
+	return outcomeDeferred, true
+}
+
+func (s *MessageService) ListSent(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Message, error) {
+	return s.repo.ListSent(ctx, tenantID, limit, offset)
+}
+
+// ListFailed returns the dead-letter queue: failed messages with their last
+// failure reason.
+func (s *MessageService) ListFailed(ctx context.Context, filter domain.MessageFilter, limit, offset int) ([]domain.Message, error) {
+	filter.Status = domain.StatusFailed
+	return s.repo.List(ctx, filter, limit, offset)
+}
+
+// Export streams every message matching filter to fn in ID order.
+func (s *MessageService) Export(ctx context.Context, filter domain.MessageFilter, fn func(domain.Message) error) error {
+	return s.repo.Export(ctx, filter, fn)
+}
+
+// RequeueRequest selects failed messages either by ID or by filter. A
+// non-nil TenantID restricts the selection to that tenant.
+type RequeueRequest struct {
+	IDs      []int64
+	Filter   *domain.MessageFilter
+	TenantID *int64
+	Actor    string
+	Reason   string
+}
+
+// Requeue moves the selected failed messages back to pending.
+func (s *MessageService) Requeue(ctx context.Context, req RequeueRequest) ([]int64, error) {
+	if len(req.IDs) == 0 && (req.Filter == nil || req.Filter.IsEmpty()) {
+		return nil, ErrNothingToRequeue
+	}
+
+	var reason *string
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	filter := req.Filter
+	if req.TenantID != nil {
+		scoped := domain.MessageFilter{}
+		if filter != nil {
+			scoped = *filter
+		}
+		scoped.TenantID = req.TenantID
+		filter = &scoped
+	}
+
+	ids, err := s.repo.Requeue(ctx, req.IDs, filter, req.Actor, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infow("Messages requeued", "count", len(ids), "by", req.Actor, "reason", req.Reason)
+
+	return ids, nil
+}
+
+// Cancel cancels a single pending message of the tenant.
+func (s *MessageService) Cancel(ctx context.Context, id int64, tenantID *int64) (domain.Message, error) {
+	msg, err := s.repo.Cancel(ctx, id, tenantID)
+	if err == nil {
+		s.log.Infow("Message cancelled", "messageID", id)
+		return msg, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return msg, err
+	}
+
+	// Nothing pending was cancelled, find out whether the message exists.
+	msg, err = s.repo.Get(ctx, id, tenantID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return msg, ErrMessageNotFound
+	case err != nil:
+		return msg, err
+	}
+
+	return msg, fmt.Errorf("%w: message is %s", ErrMessageNotCancellable, msg.Status)
+}
+
+// CancelRequest selects pending messages either by ID or by filter. A
+// non-nil TenantID restricts the selection to that tenant.
+type CancelRequest struct {
+	IDs      []int64
+	Filter   domain.MessageFilter
+	TenantID *int64
+}
+
+// CancelMatching cancels every pending message matching the request.
+func (s *MessageService) CancelMatching(ctx context.Context, req CancelRequest) ([]int64, error) {
+	if len(req.IDs) == 0 && req.Filter.IsEmpty() {
+		return nil, ErrNothingToCancel
+	}
+
+	filter := req.Filter
+	if req.TenantID != nil {
+		filter.TenantID = req.TenantID
+	}
+
+	ids, err := s.repo.CancelMatching(ctx, req.IDs, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Infow("Messages cancelled", "count", len(ids))
+
+	return ids, nil
 }
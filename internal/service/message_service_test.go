@@ -0,0 +1,190 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+type fakeRequeueRepo struct {
+	repository.MessageRepository
+	called bool
+}
+
+func (r *fakeRequeueRepo) Requeue(ctx context.Context, ids []int64, filter *domain.MessageFilter, requeuedBy string, reason *string) ([]int64, error) {
+	r.called = true
+	return ids, nil
+}
+
+type fakeSenderRegistry struct{}
+
+func (fakeSenderRegistry) SenderFor(ctx context.Context, tenantID *int64) (service.Sender, string, error) {
+	return &fakeSender{}, "default", nil
+}
+
+// fakeProcessRepo hands out msgs and fails the first failures attempts to
+// mark a message as sent.
+type fakeProcessRepo struct {
+	repository.MessageRepository
+	msgs      []domain.Message
+	failures  int
+	markCalls int
+}
+
+func (r *fakeProcessRepo) GetNextUnsent(ctx context.Context, limit int) ([]domain.Message, error) {
+	return r.msgs, nil
+}
+
+func (r *fakeProcessRepo) MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string, cost domain.MessageCost) error {
+	r.markCalls++
+	if r.markCalls <= r.failures {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func TestProcessBatchMarkAsSentFailure(t *testing.T) {
+	tests := []struct {
+		name              string
+		failures          int
+		expectedMarkCalls int
+	}{
+		{name: "First_Attempt", failures: 0, expectedMarkCalls: 1},
+		{name: "Recovers_After_Retries", failures: 2, expectedMarkCalls: 3},
+		{name: "Gives_Up", failures: 10, expectedMarkCalls: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProcessRepo{
+				msgs:     []domain.Message{{ID: 1, To: "+905551234567", Content: "Hello"}},
+				failures: tt.failures,
+			}
+			prices, err := service.NewPriceTable(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc := service.NewMessageService(repo, &fakeSuppressionRepo{}, fakeSenderRegistry{}, 10, 1, service.FrequencyCap{}, prices, zap.NewNop().Sugar())
+
+			result, err := svc.ProcessBatch(context.Background(), 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Sent != 1 {
+				t.Errorf("Sent = %d, expected 1", result.Sent)
+			}
+			if repo.markCalls != tt.expectedMarkCalls {
+				t.Errorf("MarkAsSent called %d times, expected %d", repo.markCalls, tt.expectedMarkCalls)
+			}
+		})
+	}
+}
+
+// fakeCapRepo reserves sends under a lock, like the per-recipient advisory
+// lock of the Postgres repository.
+type fakeCapRepo struct {
+	repository.MessageRepository
+	msgs []domain.Message
+
+	mu       sync.Mutex
+	reserved int
+	sent     int
+	deferred int
+}
+
+func (r *fakeCapRepo) GetNextUnsent(ctx context.Context, limit int) ([]domain.Message, error) {
+	return r.msgs, nil
+}
+
+func (r *fakeCapRepo) ReserveSend(ctx context.Context, id int64, to string, since time.Time, maxMessages int) (bool, int, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reserved >= maxMessages {
+		return false, r.reserved, time.Now(), nil
+	}
+	r.reserved++
+	return true, r.reserved - 1, time.Now(), nil
+}
+
+func (r *fakeCapRepo) MarkAsSent(ctx context.Context, id int64, sentAt time.Time, externalID *string, cost domain.MessageCost) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent++
+	return nil
+}
+
+func (r *fakeCapRepo) Defer(ctx context.Context, id int64, notBefore time.Time, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deferred++
+	return nil
+}
+
+func TestProcessBatchFrequencyCapConcurrent(t *testing.T) {
+	var msgs []domain.Message
+	for i := range 20 {
+		msgs = append(msgs, domain.Message{ID: int64(i + 1), To: "+905551234567", Content: "Hello"})
+	}
+
+	repo := &fakeCapRepo{msgs: msgs}
+	prices, err := service.NewPriceTable(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frequencyCap := service.FrequencyCap{MaxMessages: 3, Window: time.Hour, Policy: service.FrequencyCapDefer}
+	svc := service.NewMessageService(repo, &fakeSuppressionRepo{}, fakeSenderRegistry{}, len(msgs), 8, frequencyCap, prices, zap.NewNop().Sugar())
+
+	result, err := svc.ProcessBatch(context.Background(), len(msgs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Sent != 3 || repo.sent != 3 {
+		t.Errorf("sent %d (recorded %d), expected 3", result.Sent, repo.sent)
+	}
+	if result.Deferred != 17 || repo.deferred != 17 {
+		t.Errorf("deferred %d (recorded %d), expected 17", result.Deferred, repo.deferred)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	tenantID := int64(7)
+
+	tests := []struct {
+		name        string
+		req         service.RequeueRequest
+		expectedErr error
+	}{
+		{name: "IDs", req: service.RequeueRequest{IDs: []int64{1, 2}}},
+		{name: "Filter", req: service.RequeueRequest{Filter: &domain.MessageFilter{To: "+905551234567"}}},
+		{name: "Nothing", req: service.RequeueRequest{}, expectedErr: service.ErrNothingToRequeue},
+		{name: "Empty_Filter", req: service.RequeueRequest{Filter: &domain.MessageFilter{}}, expectedErr: service.ErrNothingToRequeue},
+		{
+			name:        "Tenant_Only_Filter",
+			req:         service.RequeueRequest{Filter: &domain.MessageFilter{TenantID: &tenantID}, TenantID: &tenantID},
+			expectedErr: service.ErrNothingToRequeue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRequeueRepo{}
+			svc := service.NewMessageService(repo, nil, nil, 10, 1, service.FrequencyCap{}, nil, zap.NewNop().Sugar())
+
+			_, err := svc.Requeue(context.Background(), tt.req)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Requeue() error = %v, expected %v", err, tt.expectedErr)
+			}
+			if repo.called != (tt.expectedErr == nil) {
+				t.Errorf("repository called = %v, expected %v", repo.called, tt.expectedErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/infra/oidc"
+	"go.uber.org/zap"
+)
+
+const PrincipalOIDC = "oidc"
+
+// TokenVerifier verifies a bearer JWT and returns its claims.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (oidc.Claims, error)
+}
+
+// OIDCService authenticates operators by identity provider tokens. Roles in
+// the token are mapped to scopes by the configured role table.
+type OIDCService struct {
+	verifier   TokenVerifier
+	rolesClaim string
+	nameClaim  string
+	roleScopes map[string][]string
+	log        *zap.SugaredLogger
+}
+
+func NewOIDCService(verifier TokenVerifier, rolesClaim, nameClaim string, roleScopes map[string][]string, log *zap.SugaredLogger) *OIDCService {
+	return &OIDCService{
+		verifier:   verifier,
+		rolesClaim: rolesClaim,
+		nameClaim:  nameClaim,
+		roleScopes: roleScopes,
+		log:        log,
+	}
+}
+
+// Authenticate verifies token and resolves it to a principal.
+func (s *OIDCService) Authenticate(ctx context.Context, token string) (domain.Principal, error) {
+	claims, err := s.verifier.Verify(ctx, token)
+	if err != nil {
+		s.log.Infow("OIDC token rejected", "ERROR", err)
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	subject := claims.String("sub")
+	if subject == "" {
+		return domain.Principal{}, ErrUnauthenticated
+	}
+
+	name := claims.String(s.nameClaim)
+	if name == "" {
+		name = subject
+	}
+
+	return domain.Principal{
+		Type:   PrincipalOIDC,
+		ID:     subject,
+		Name:   name,
+		Scopes: s.scopes(claims.Strings(s.rolesClaim)),
+	}, nil
+}
+
+func (s *OIDCService) scopes(roles []string) []string {
+	var scopes []string
+	for _, role := range roles {
+		for _, scope := range s.roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// ValidateRoleScopes reports role mappings that name unknown scopes.
+func ValidateRoleScopes(roleScopes map[string][]string) error {
+	for role, scopes := range roleScopes {
+		for _, scope := range scopes {
+			if !slices.Contains(domain.Scopes, scope) {
+				return fmt.Errorf("role %q: unknown scope %q", role, scope)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidPrice = errors.New("invalid price")
+
+// Price is the price of one segment sent through Provider to numbers
+// starting with Prefix. An empty Provider matches every provider.
+type Price struct {
+	Provider   string
+	Prefix     string
+	PerSegment float64
+}
+
+// PriceTable looks up the price per segment of a message.
+type PriceTable struct {
+	prices []Price
+}
+
+// NewPriceTable validates prices. Prefixes are compared by their digits
+// only, so "+90" and "90" are the same prefix.
+func NewPriceTable(prices []Price) (*PriceTable, error) {
+	table := &PriceTable{prices: make([]Price, 0, len(prices))}
+
+	for _, p := range prices {
+		if p.PerSegment < 0 {
+			return nil, fmt.Errorf("%w: prefix %q has a negative price", ErrInvalidPrice, p.Prefix)
+		}
+		p.Prefix = digits(p.Prefix)
+		table.prices = append(table.prices, p)
+	}
+
+	return table, nil
+}
+
+// Lookup returns the price per segment for a recipient. The longest
+// matching prefix wins, and on equal prefixes an entry for the provider wins
+// over one for every provider.
+func (t *PriceTable) Lookup(provider, to string) (float64, bool) {
+	number := digits(to)
+
+	var best *Price
+	for i := range t.prices {
+		p := &t.prices[i]
+		if p.Provider != "" && p.Provider != provider {
+			continue
+		}
+		if !strings.HasPrefix(number, p.Prefix) {
+			continue
+		}
+		if best == nil ||
+			len(p.Prefix) > len(best.Prefix) ||
+			(len(p.Prefix) == len(best.Prefix) && best.Provider == "" && p.Provider != "") {
+			best = p
+		}
+	}
+
+	if best == nil {
+		return 0, false
+	}
+	return best.PerSegment, true
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/service"
+)
+
+func TestPriceTableLookup(t *testing.T) {
+	table, err := service.NewPriceTable([]service.Price{
+		{Prefix: "+90", PerSegment: 0.012},
+		{Prefix: "+90 532", PerSegment: 0.02},
+		{Provider: "acme", Prefix: "+90", PerSegment: 0.01},
+		{Provider: "acme", Prefix: "+", PerSegment: 0.05},
+	})
+	if err != nil {
+		t.Fatalf("NewPriceTable() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		to       string
+		price    float64
+		found    bool
+	}{
+		{"any provider", "default", "+905551234567", 0.012, true},
+		{"longest prefix", "default", "+905321234567", 0.02, true},
+		{"longest prefix beats provider", "acme", "+905321234567", 0.02, true},
+		{"provider on equal prefix", "acme", "+905551234567", 0.01, true},
+		{"provider catch-all", "acme", "+491701234567", 0.05, true},
+		{"no price", "default", "+491701234567", 0, false},
+		{"without plus", "default", "905551234567", 0.012, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, found := table.Lookup(tt.provider, tt.to)
+			if price != tt.price || found != tt.found {
+				t.Errorf("Lookup(%q, %q) = %v, %v, expected %v, %v", tt.provider, tt.to, price, found, tt.price, tt.found)
+			}
+		})
+	}
+}
+
+func TestNewPriceTableRejectsNegativePrices(t *testing.T) {
+	_, err := service.NewPriceTable([]service.Price{{Prefix: "+90", PerSegment: -1}})
+	if !errors.Is(err, service.ErrInvalidPrice) {
+		t.Errorf("NewPriceTable() error = %v, expected ErrInvalidPrice", err)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/infra/logger"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PrivacyService erases the personal data stored for a phone number.
+type PrivacyService struct {
+	repo repository.PrivacyRepository
+	log  *zap.SugaredLogger
+}
+
+func NewPrivacyService(repo repository.PrivacyRepository, log *zap.SugaredLogger) *PrivacyService {
+	return &PrivacyService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Forget anonymizes everything stored for number. Message rows are kept with
+// their status and cost, so reports stay correct, but lose the number and the
+// content. A suppression of the number is kept on purpose: forgetting a
+// number must not opt it back in.
+func (s *PrivacyService) Forget(ctx context.Context, number string) (domain.ForgetResult, error) {
+	if err := ValidateNumber(number); err != nil {
+		return domain.ForgetResult{}, err
+	}
+
+	result, err := s.repo.Forget(ctx, number)
+	if err != nil {
+		return result, err
+	}
+
+	s.log.Infow("Number forgotten",
+		"number", logger.MaskNumber(number),
+		"messages", result.Messages,
+		"cancelled", result.Cancelled,
+		"archivedMessages", result.ArchivedMessages,
+		"inboundMessages", result.InboundMessages,
+		"importErrors", result.ImportErrors,
+	)
+
+	return result, nil
+}
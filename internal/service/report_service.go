@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidReport = errors.New("invalid report")
+
+// defaultStatsRange is the range of statistics requested without one.
+const defaultStatsRange = 24 * time.Hour
+
+// ReportService builds reports over sent messages.
+type ReportService struct {
+	repo     repository.ReportRepository
+	currency string
+	log      *zap.SugaredLogger
+}
+
+func NewReportService(repo repository.ReportRepository, currency string, log *zap.SugaredLogger) *ReportService {
+	return &ReportService{
+		repo:     repo,
+		currency: currency,
+		log:      log,
+	}
+}
+
+// Currency is the currency of the configured prices and so of every cost.
+func (s *ReportService) Currency() string {
+	return s.currency
+}
+
+// Cost reports what sent messages cost, grouped by day unless filter.GroupBy
+// says otherwise.
+func (s *ReportService) Cost(ctx context.Context, filter domain.CostFilter) ([]domain.CostRecord, error) {
+	if len(filter.GroupBy) == 0 {
+		filter.GroupBy = []string{domain.CostGroupDay}
+	}
+	for _, group := range filter.GroupBy {
+		if !slices.Contains(domain.CostGroups, group) {
+			return nil, fmt.Errorf("%w: unknown group %q", ErrInvalidReport, group)
+		}
+	}
+	if filter.From != nil && filter.Until != nil && !filter.From.Before(*filter.Until) {
+		return nil, fmt.Errorf("%w: from must be before until", ErrInvalidReport)
+	}
+
+	return s.repo.Cost(ctx, filter)
+}
+
+// Stats reports how the messages created in the filter's range fared,
+// within the last day unless a range is given, and the current backlog.
+func (s *ReportService) Stats(ctx context.Context, filter domain.StatsFilter) (domain.StatsReport, error) {
+	now := time.Now().UTC()
+	if filter.Until.IsZero() {
+		filter.Until = now
+	}
+	if filter.From.IsZero() {
+		filter.From = filter.Until.Add(-defaultStatsRange)
+	}
+	if !filter.From.Before(filter.Until) {
+		return domain.StatsReport{}, fmt.Errorf("%w: from must be before until", ErrInvalidReport)
+	}
+	if filter.GroupBy != "" && !slices.Contains(domain.StatsGroups, filter.GroupBy) {
+		return domain.StatsReport{}, fmt.Errorf("%w: unknown group %q", ErrInvalidReport, filter.GroupBy)
+	}
+
+	report := domain.StatsReport{From: filter.From, Until: filter.Until, GroupBy: filter.GroupBy}
+
+	totalsFilter := filter
+	totalsFilter.GroupBy = ""
+	totals, err := s.repo.Stats(ctx, totalsFilter)
+	if err != nil {
+		return report, err
+	}
+	if len(totals) > 0 {
+		report.Totals = withRates(totals[0], filter.Until.Sub(filter.From))
+	}
+
+	if filter.GroupBy != "" {
+		groups, err := s.repo.Stats(ctx, filter)
+		if err != nil {
+			return report, err
+		}
+		for _, g := range groups {
+			report.Groups = append(report.Groups, withRates(g, groupDuration(g, filter)))
+		}
+	}
+
+	report.Backlog, err = s.repo.Backlog(ctx, filter.TenantID)
+	if err != nil {
+		return report, err
+	}
+	if report.Backlog.OldestPendingAt != nil {
+		report.Backlog.OldestAgeSeconds = now.Sub(*report.Backlog.OldestPendingAt).Seconds()
+	}
+
+	return report, nil
+}
+
+// groupDuration is the part of the range a group covers: its bucket for time
+// groups, clipped to the range, and the whole range otherwise.
+func groupDuration(g domain.Stats, filter domain.StatsFilter) time.Duration {
+	if g.Bucket == nil {
+		return filter.Until.Sub(filter.From)
+	}
+
+	length := time.Hour
+	if filter.GroupBy == domain.StatsGroupDay {
+		length = 24 * time.Hour
+	}
+
+	start := *g.Bucket
+	if start.Before(filter.From) {
+		start = filter.From
+	}
+	end := g.Bucket.Add(length)
+	if end.After(filter.Until) {
+		end = filter.Until
+	}
+	return end.Sub(start)
+}
+
+// withRates fills in the send rates over d and the share of finished
+// messages that failed.
+func withRates(s domain.Stats, d time.Duration) domain.Stats {
+	sent := float64(s.Sent + s.Delivered)
+	if d > 0 {
+		s.SentPerMinute = sent / d.Minutes()
+		s.SentPerHour = sent / d.Hours()
+	}
+	if finished := sent + float64(s.Failed); finished > 0 {
+		s.FailureRate = float64(s.Failed) / finished
+	}
+	return s
+}
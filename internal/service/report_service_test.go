@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+type fakeReportRepo struct {
+	repository.ReportRepository
+	totals  domain.Stats
+	groups  []domain.Stats
+	backlog domain.Backlog
+}
+
+func (r *fakeReportRepo) Stats(ctx context.Context, filter domain.StatsFilter) ([]domain.Stats, error) {
+	if filter.GroupBy == "" {
+		return []domain.Stats{r.totals}, nil
+	}
+	return r.groups, nil
+}
+
+func (r *fakeReportRepo) Backlog(ctx context.Context, tenantID *int64) (domain.Backlog, error) {
+	return r.backlog, nil
+}
+
+func TestReportServiceStats(t *testing.T) {
+	from := time.Date(2026, 5, 1, 10, 30, 0, 0, time.UTC)
+	until := from.Add(2 * time.Hour)
+	firstHour := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	secondHour := firstHour.Add(time.Hour)
+
+	repo := &fakeReportRepo{
+		totals: domain.Stats{StatusCounts: domain.StatusCounts{Sent: 90, Delivered: 30, Failed: 30, Pending: 5}},
+		groups: []domain.Stats{
+			// Only 30 minutes of the first bucket are in range.
+			{Bucket: &firstHour, StatusCounts: domain.StatusCounts{Sent: 30}},
+			{Bucket: &secondHour, StatusCounts: domain.StatusCounts{Sent: 60, Failed: 20}},
+		},
+	}
+	reports := service.NewReportService(repo, "EUR", zap.NewNop().Sugar())
+
+	report, err := reports.Stats(context.Background(), domain.StatsFilter{From: from, Until: until, GroupBy: domain.StatsGroupHour})
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if report.Totals.SentPerHour != 60 || report.Totals.SentPerMinute != 1 {
+		t.Errorf("totals rate = %v/h, %v/min, expected 60/h, 1/min", report.Totals.SentPerHour, report.Totals.SentPerMinute)
+	}
+	if report.Totals.FailureRate != 0.2 {
+		t.Errorf("totals failure rate = %v, expected 0.2", report.Totals.FailureRate)
+	}
+	if len(report.Groups) != 2 {
+		t.Fatalf("got %d groups, expected 2", len(report.Groups))
+	}
+	if got := report.Groups[0].SentPerHour; got != 60 {
+		t.Errorf("partial bucket rate = %v/h, expected 60/h", got)
+	}
+	if got := report.Groups[1].FailureRate; got != 0.25 {
+		t.Errorf("second bucket failure rate = %v, expected 0.25", got)
+	}
+}
+
+func TestReportServiceStatsRejectsInvalidFilters(t *testing.T) {
+	reports := service.NewReportService(&fakeReportRepo{}, "EUR", zap.NewNop().Sugar())
+	now := time.Now()
+
+	filters := []domain.StatsFilter{
+		{From: now, Until: now.Add(-time.Hour)},
+		{GroupBy: "week"},
+	}
+	for _, filter := range filters {
+		if _, err := reports.Stats(context.Background(), filter); !errors.Is(err, service.ErrInvalidReport) {
+			t.Errorf("Stats(%+v) error = %v, expected ErrInvalidReport", filter, err)
+		}
+	}
+}
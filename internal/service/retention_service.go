@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidRetention = errors.New("invalid retention policy")
+
+// retainedStatuses are the statuses messages never leave on their own, and
+// so the only ones retention may purge.
+var retainedStatuses = []domain.MessageStatus{
+	domain.StatusSent,
+	domain.StatusDelivered,
+	domain.StatusFailed,
+	domain.StatusCancelled,
+	domain.StatusSuppressed,
+	domain.StatusDuplicate,
+}
+
+// RetentionPolicy selects the messages to purge: those with one of Statuses
+// that finished more than After ago.
+type RetentionPolicy struct {
+	Mode       string
+	After      time.Duration
+	Statuses   []domain.MessageStatus
+	BatchSize  int
+	MaxBatches int
+}
+
+func (p RetentionPolicy) Validate() error {
+	if p.Mode != domain.RetentionArchive && p.Mode != domain.RetentionDelete {
+		return fmt.Errorf("%w: mode must be %q or %q", ErrInvalidRetention, domain.RetentionArchive, domain.RetentionDelete)
+	}
+	if p.After <= 0 {
+		return fmt.Errorf("%w: retention period must be positive", ErrInvalidRetention)
+	}
+	if len(p.Statuses) == 0 {
+		return fmt.Errorf("%w: at least one status is required", ErrInvalidRetention)
+	}
+	for _, status := range p.Statuses {
+		if !slices.Contains(retainedStatuses, status) {
+			return fmt.Errorf("%w: %q messages cannot be purged", ErrInvalidRetention, status)
+		}
+	}
+	if p.BatchSize <= 0 || p.MaxBatches <= 0 {
+		return fmt.Errorf("%w: batch size and max batches must be positive", ErrInvalidRetention)
+	}
+	return nil
+}
+
+// RetentionService purges old messages in bounded batches, so no run holds
+// locks on many rows at once. Only the leader purges.
+type RetentionService struct {
+	repo    repository.RetentionRepository
+	elector LeaderElector
+	policy  RetentionPolicy
+	log     *zap.SugaredLogger
+}
+
+func NewRetentionService(repo repository.RetentionRepository, elector LeaderElector, policy RetentionPolicy, log *zap.SugaredLogger) *RetentionService {
+	return &RetentionService{
+		repo:    repo,
+		elector: elector,
+		policy:  policy,
+		log:     log,
+	}
+}
+
+// Run purges batches until none is full or MaxBatches is reached, and
+// records what it purged. The rest is left to the next run.
+func (s *RetentionService) Run(ctx context.Context) error {
+	if !s.elector.IsLeader() {
+		return nil
+	}
+
+	run := domain.RetentionRun{
+		Mode:      s.policy.Mode,
+		Cutoff:    time.Now().UTC().Add(-s.policy.After),
+		ByStatus:  make(map[domain.MessageStatus]int),
+		StartedAt: time.Now().UTC(),
+	}
+
+	var runErr error
+	for run.Batches < s.policy.MaxBatches {
+		purged, err := s.repo.PurgeBatch(ctx, s.policy.Statuses, run.Cutoff, s.policy.BatchSize, s.policy.Mode == domain.RetentionArchive)
+		if err != nil {
+			runErr = err
+			break
+		}
+		run.Batches++
+
+		batch := 0
+		for status, count := range purged {
+			run.ByStatus[status] += count
+			batch += count
+		}
+		run.Purged += batch
+
+		if batch < s.policy.BatchSize {
+			break
+		}
+	}
+
+	if runErr != nil {
+		msg := runErr.Error()
+		run.Error = &msg
+		s.log.Errorw("Retention run failed", "purged", run.Purged, "error", runErr)
+	}
+
+	// Only runs that did something are worth a record.
+	if run.Purged > 0 || runErr != nil {
+		if _, err := s.repo.CreateRun(context.WithoutCancel(ctx), run); err != nil {
+			s.log.Errorw("Unable to record retention run", "error", err)
+		}
+	}
+
+	s.log.Infow("Retention run finished", "mode", run.Mode, "cutoff", run.Cutoff, "purged", run.Purged, "byStatus", run.ByStatus, "batches", run.Batches)
+
+	return runErr
+}
+
+func (s *RetentionService) ListRuns(ctx context.Context, limit, offset int) ([]domain.RetentionRun, error) {
+	return s.repo.ListRuns(ctx, limit, offset)
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+	"go.uber.org/zap"
+)
+
+type fakeElector struct {
+	service.LeaderElector
+	leader bool
+}
+
+func (e *fakeElector) IsLeader() bool { return e.leader }
+
+type fakeRetentionRepo struct {
+	repository.RetentionRepository
+	remaining int
+	batches   int
+	runs      []domain.RetentionRun
+}
+
+func (r *fakeRetentionRepo) PurgeBatch(ctx context.Context, statuses []domain.MessageStatus, cutoff time.Time, limit int, archive bool) (map[domain.MessageStatus]int, error) {
+	r.batches++
+	n := min(limit, r.remaining)
+	r.remaining -= n
+	return map[domain.MessageStatus]int{domain.StatusSent: n}, nil
+}
+
+func (r *fakeRetentionRepo) CreateRun(ctx context.Context, run domain.RetentionRun) (domain.RetentionRun, error) {
+	r.runs = append(r.runs, run)
+	return run, nil
+}
+
+func TestRetentionServiceRun(t *testing.T) {
+	policy := service.RetentionPolicy{
+		Mode:       domain.RetentionArchive,
+		After:      24 * time.Hour,
+		Statuses:   []domain.MessageStatus{domain.StatusSent},
+		BatchSize:  10,
+		MaxBatches: 3,
+	}
+
+	tests := []struct {
+		name      string
+		leader    bool
+		remaining int
+		batches   int
+		purged    int
+	}{
+		{"follower does nothing", false, 25, 0, 0},
+		{"stops at a partial batch", true, 25, 3, 25},
+		{"stops at max batches", true, 45, 3, 30},
+		{"stops at an exactly full last batch", true, 10, 2, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRetentionRepo{remaining: tt.remaining}
+			retention := service.NewRetentionService(repo, &fakeElector{leader: tt.leader}, policy, zap.NewNop().Sugar())
+
+			if err := retention.Run(context.Background()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if repo.batches != tt.batches {
+				t.Errorf("batches = %d, expected %d", repo.batches, tt.batches)
+			}
+
+			purged := 0
+			for _, run := range repo.runs {
+				purged += run.Purged
+			}
+			if purged != tt.purged {
+				t.Errorf("recorded %d purged messages, expected %d", purged, tt.purged)
+			}
+		})
+	}
+}
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	valid := service.RetentionPolicy{
+		Mode:       domain.RetentionDelete,
+		After:      time.Hour,
+		Statuses:   []domain.MessageStatus{domain.StatusFailed},
+		BatchSize:  100,
+		MaxBatches: 1,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	pending := valid
+	pending.Statuses = []domain.MessageStatus{domain.StatusPending}
+	mode := valid
+	mode.Mode = "truncate"
+
+	for _, p := range []service.RetentionPolicy{pending, mode} {
+		if err := p.Validate(); !errors.Is(err, service.ErrInvalidRetention) {
+			t.Errorf("Validate(%+v) error = %v, expected ErrInvalidRetention", p, err)
+		}
+	}
+}
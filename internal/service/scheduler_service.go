@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSchedulerAlreadyRunning = errors.New("scheduler is already running")
+	ErrSchedulerAlreadyStopped = errors.New("scheduler is already stopped")
+)
+
+// LocalScheduler is the ticker running on this replica.
+type LocalScheduler interface {
+	Start() error
+	Stop() error
+	IsRunning() bool
+}
+
+type LeaderElector interface {
+	Elect(ctx context.Context) (bool, error)
+	IsLeader() bool
+	Resign(ctx context.Context) error
+}
+
+// SchedulerStatus combines the desired state shared by all replicas with the
+// state of this replica.
+type SchedulerStatus struct {
+	Running   bool      `json:"running"`
+	Leader    bool      `json:"leader"`
+	Active    bool      `json:"active"`
+	UpdatedBy string    `json:"updated_by"`
+	Reason    *string   `json:"reason,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SchedulerChange describes who changes the desired state and why.
+type SchedulerChange struct {
+	Actor  string
+	Reason string
+}
+
+func (c SchedulerChange) reason() *string {
+	if c.Reason == "" {
+		return nil
+	}
+	return &c.Reason
+}
+
+// SchedulerService keeps the local scheduler in line with the persisted
+// desired state: it only ticks on the elected leader and only while running.
+type SchedulerService struct {
+	repo      repository.SchedulerStateRepository
+	scheduler LocalScheduler
+	elector   LeaderElector
+	log       *zap.SugaredLogger
+}
+
+func NewSchedulerService(repo repository.SchedulerStateRepository, scheduler LocalScheduler, elector LeaderElector, log *zap.SugaredLogger) *SchedulerService {
+	return &SchedulerService{
+		repo:      repo,
+		scheduler: scheduler,
+		elector:   elector,
+		log:       log,
+	}
+}
+
+// Reconcile renews leadership and starts or stops the local scheduler.
+func (s *SchedulerService) Reconcile(ctx context.Context) error {
+	leader, err := s.elector.Elect(ctx)
+	if err != nil {
+		s.log.Warnw("Leader election failed", "error", err)
+	}
+
+	state, err := s.repo.Get(ctx)
+	if err != nil {
+		s.log.Errorw("Reconcile", "ERROR", err)
+		return err
+	}
+
+	shouldRun := leader && state.Running
+
+	switch {
+	case shouldRun && !s.scheduler.IsRunning():
+		s.log.Infow("Starting local scheduler", "leader", leader)
+		_ = s.scheduler.Start()
+	case !shouldRun && s.scheduler.IsRunning():
+		s.log.Infow("Stopping local scheduler", "leader", leader, "desiredRunning", state.Running)
+		_ = s.scheduler.Stop()
+	}
+
+	return nil
+}
+
+func (s *SchedulerService) Start(ctx context.Context, change SchedulerChange) error {
+	changed, err := s.repo.SetRunning(ctx, true, change.Actor, change.reason())
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrSchedulerAlreadyRunning
+	}
+
+	s.log.Infow("Scheduler resumed", "by", change.Actor, "reason", change.Reason)
+
+	// The state is persisted at this point; a failed reconcile is retried by
+	// the periodic reconciler.
+	_ = s.Reconcile(ctx)
+
+	return nil
+}
+
+func (s *SchedulerService) Stop(ctx context.Context, change SchedulerChange) error {
+	changed, err := s.repo.SetRunning(ctx, false, change.Actor, change.reason())
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrSchedulerAlreadyStopped
+	}
+
+	s.log.Warnw("Scheduler paused", "by", change.Actor, "reason", change.Reason)
+
+	// The state is persisted at this point; a failed reconcile is retried by
+	// the periodic reconciler.
+	_ = s.Reconcile(ctx)
+
+	return nil
+}
+
+// IsLeader reports whether this replica holds the leadership and thereby owns
+// message processing.
+func (s *SchedulerService) IsLeader() bool {
+	return s.elector.IsLeader()
+}
+
+func (s *SchedulerService) Status(ctx context.Context) (SchedulerStatus, error) {
+	state, err := s.repo.Get(ctx)
+	if err != nil {
+		return SchedulerStatus{}, err
+	}
+
+	return SchedulerStatus{
+		Running:   state.Running,
+		Leader:    s.elector.IsLeader(),
+		Active:    s.scheduler.IsRunning(),
+		UpdatedBy: state.UpdatedBy,
+		Reason:    state.Reason,
+		UpdatedAt: state.UpdatedAt,
+	}, nil
+}
+
+// LogStartupState reports a paused scheduler on boot, so a restart never
+// resumes sending silently and never leaves it paused without a trace.
+func (s *SchedulerService) LogStartupState(ctx context.Context) {
+	state, err := s.repo.Get(ctx)
+	if err != nil {
+		s.log.Errorw("LogStartupState", "ERROR", err)
+		return
+	}
+
+	if state.Running {
+		return
+	}
+
+	reason := ""
+	if state.Reason != nil {
+		reason = *state.Reason
+	}
+
+	s.log.Warnw("startup", "status", "scheduler is paused", "by", state.UpdatedBy, "since", state.UpdatedAt, "reason", reason)
+}
+
+// Shutdown stops the local scheduler and hands leadership over.
+func (s *SchedulerService) Shutdown(ctx context.Context) error {
+	if s.scheduler.IsRunning() {
+		_ = s.scheduler.Stop()
+	}
+	return s.elector.Resign(ctx)
+}
@@ -0,0 +1,71 @@
+package service
+
+import "strings"
+
+// Encoding is the character set a message is sent with.
+type Encoding string
+
+const (
+	EncodingGSM7 Encoding = "gsm7"
+	EncodingUCS2 Encoding = "ucs2"
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet; gsm7Extended characters are
+// sent as an escape sequence and take two septets.
+const (
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// Single and concatenated message capacities. Concatenated parts lose room
+// to the user data header.
+const (
+	gsm7Single    = 160
+	gsm7Multipart = 153
+	ucs2Single    = 70
+	ucs2Multipart = 67
+)
+
+// Segments returns the number of SMS parts content is split into and the
+// encoding it is sent with.
+func Segments(content string) (int, Encoding) {
+	if content == "" {
+		return 0, EncodingGSM7
+	}
+
+	septets := 0
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			return parts(ucs2Units(content), ucs2Single, ucs2Multipart), EncodingUCS2
+		}
+	}
+
+	return parts(septets, gsm7Single, gsm7Multipart), EncodingGSM7
+}
+
+// ucs2Units counts UTF-16 code units; characters outside the basic
+// multilingual plane take two.
+func ucs2Units(content string) int {
+	n := 0
+	for _, r := range content {
+		if r > 0xFFFF {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func parts(length, single, multipart int) int {
+	if length <= single {
+		return 1
+	}
+	return (length + multipart - 1) / multipart
+}
@@ -0,0 +1,39 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/service"
+)
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		segments int
+		encoding service.Encoding
+	}{
+		{"Empty", "", 0, service.EncodingGSM7},
+		{"Short_GSM7", "Your code is 1234", 1, service.EncodingGSM7},
+		{"Full_GSM7", strings.Repeat("a", 160), 1, service.EncodingGSM7},
+		{"Two_Part_GSM7", strings.Repeat("a", 161), 2, service.EncodingGSM7},
+		{"Extended_Chars_Take_Two_Septets", strings.Repeat("€", 80), 1, service.EncodingGSM7},
+		{"Extended_Chars_Overflow", strings.Repeat("€", 81), 2, service.EncodingGSM7},
+		{"Three_Part_GSM7", strings.Repeat("a", 307), 3, service.EncodingGSM7},
+		{"Full_UCS2", strings.Repeat("ა", 70), 1, service.EncodingUCS2},
+		{"Two_Part_UCS2", strings.Repeat("ა", 71), 2, service.EncodingUCS2},
+		{"Three_Part_UCS2", strings.Repeat("ა", 135), 3, service.EncodingUCS2},
+		{"Emoji_Is_Two_Units", strings.Repeat("😀", 35), 1, service.EncodingUCS2},
+		{"Emoji_Overflow", strings.Repeat("😀", 36), 2, service.EncodingUCS2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, encoding := service.Segments(tt.content)
+			if segments != tt.segments || encoding != tt.encoding {
+				t.Errorf("Segments() = %d, %s, expected %d, %s", segments, encoding, tt.segments, tt.encoding)
+			}
+		})
+	}
+}
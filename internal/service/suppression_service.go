@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSuppressionNotFound = errors.New("suppression not found")
+	ErrSuppressionExists   = errors.New("number is already suppressed")
+)
+
+type SuppressionService struct {
+	repo repository.SuppressionRepository
+	log  *zap.SugaredLogger
+}
+
+func NewSuppressionService(repo repository.SuppressionRepository, log *zap.SugaredLogger) *SuppressionService {
+	return &SuppressionService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *SuppressionService) Create(ctx context.Context, sup domain.Suppression) (domain.Suppression, error) {
+	if err := ValidateNumber(sup.Number); err != nil {
+		return domain.Suppression{}, err
+	}
+	if sup.Source == "" {
+		sup.Source = domain.SuppressionSourceAPI
+	}
+
+	created, err := s.repo.Create(ctx, sup)
+	if errors.Is(err, repository.ErrConflict) {
+		return created, ErrSuppressionExists
+	}
+	if err != nil {
+		return created, err
+	}
+
+	s.log.Infow("Number suppressed", "source", created.Source, "reason", created.Reason)
+
+	return created, nil
+}
+
+func (s *SuppressionService) Get(ctx context.Context, number string) (domain.Suppression, error) {
+	sup, err := s.repo.Get(ctx, number)
+	if errors.Is(err, repository.ErrNotFound) {
+		return sup, ErrSuppressionNotFound
+	}
+	return sup, err
+}
+
+func (s *SuppressionService) List(ctx context.Context, limit, offset int) ([]domain.Suppression, error) {
+	return s.repo.List(ctx, limit, offset)
+}
+
+func (s *SuppressionService) Update(ctx context.Context, number, reason string) (domain.Suppression, error) {
+	sup, err := s.repo.Update(ctx, number, reason)
+	if errors.Is(err, repository.ErrNotFound) {
+		return sup, ErrSuppressionNotFound
+	}
+	return sup, err
+}
+
+func (s *SuppressionService) Delete(ctx context.Context, number string) error {
+	err := s.repo.Delete(ctx, number)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrSuppressionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.log.Infow("Number removed from suppression list")
+
+	return nil
+}
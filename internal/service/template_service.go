@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateExists   = errors.New("template with this name and locale already exists")
+	ErrTemplateInvalid  = errors.New("invalid template")
+)
+
+type TemplateService struct {
+	repo repository.TemplateRepository
+	log  *zap.SugaredLogger
+}
+
+func NewTemplateService(repo repository.TemplateRepository, log *zap.SugaredLogger) *TemplateService {
+	return &TemplateService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *TemplateService) Create(ctx context.Context, t domain.Template) (domain.Template, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	t.Locale = strings.TrimSpace(t.Locale)
+
+	if t.Name == "" || t.Locale == "" || t.Body == "" {
+		return domain.Template{}, fmt.Errorf("%w: name, locale and body are required", ErrTemplateInvalid)
+	}
+
+	if _, err := parseTemplate(t.Body); err != nil {
+		return domain.Template{}, fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+
+	created, err := s.repo.Create(ctx, t)
+	if errors.Is(err, repository.ErrConflict) {
+		return created, ErrTemplateExists
+	}
+	if errors.Is(err, repository.ErrInvalidReference) {
+		return created, ErrUnknownTenant
+	}
+	if err != nil {
+		return created, err
+	}
+
+	s.log.Infow("Template created", "templateID", created.ID, "name", created.Name, "locale", created.Locale)
+
+	return created, nil
+}
+
+// Get, List and Delete only see templates of tenantID unless it is nil.
+func (s *TemplateService) Get(ctx context.Context, id int64, tenantID *int64) (domain.Template, error) {
+	t, err := s.repo.Get(ctx, id, tenantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return t, ErrTemplateNotFound
+	}
+	return t, err
+}
+
+func (s *TemplateService) List(ctx context.Context, tenantID *int64, limit, offset int) ([]domain.Template, error) {
+	return s.repo.List(ctx, tenantID, limit, offset)
+}
+
+func (s *TemplateService) Delete(ctx context.Context, id int64, tenantID *int64) error {
+	err := s.repo.Delete(ctx, id, tenantID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrTemplateNotFound
+	}
+	return err
+}
+
+// Render renders the template with vars and validates the resulting content.
+// The template must belong to tenantID, the owner of the message; a nil
+// tenantID is the default tenant.
+func (s *TemplateService) Render(ctx context.Context, id int64, tenantID *int64, vars map[string]string) (string, error) {
+	t, err := s.Get(ctx, id, tenantID)
+	if err != nil {
+		return "", err
+	}
+	if (t.TenantID == nil) != (tenantID == nil) {
+		return "", ErrTemplateNotFound
+	}
+
+	return RenderTemplate(t.Body, vars)
+}
+
+// RenderTemplate renders body with vars. Every placeholder must have a value.
+func RenderTemplate(body string, vars map[string]string) (string, error) {
+	tmpl, err := parseTemplate(body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateInvalid, err)
+	}
+
+	content := sb.String()
+	if err := ValidateContent(content); err != nil {
+		return "", fmt.Errorf("rendered content: %w", err)
+	}
+
+	return content, nil
+}
+
+// parseTemplate only accepts {{.name}} placeholders. Other actions, such as
+// range or printf, could render content of any size before it is validated.
+func parseTemplate(body string) (*template.Template, error) {
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.Tree == nil {
+		return tmpl, nil
+	}
+
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+		case *parse.ActionNode:
+			if !isPlaceholder(n) {
+				return nil, fmt.Errorf("only {{.name}} placeholders are allowed, got %s", n)
+			}
+		default:
+			return nil, fmt.Errorf("only {{.name}} placeholders are allowed, got %s", n)
+		}
+	}
+
+	return tmpl, nil
+}
+
+func isPlaceholder(n *parse.ActionNode) bool {
+	if len(n.Pipe.Decl) != 0 || len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	field, ok := n.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	return ok && len(field.Ident) == 1
+}
@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/service"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		vars        map[string]string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Substitutes_Variables",
+			body:     "Hello {{.name}}, your code is {{.code}}",
+			vars:     map[string]string{"name": "Ana", "code": "1234"},
+			expected: "Hello Ana, your code is 1234",
+		},
+		{
+			name:     "Unicode_Content",
+			body:     "გამარჯობა {{.name}}",
+			vars:     map[string]string{"name": "ნინო"},
+			expected: "გამარჯობა ნინო",
+		},
+		{
+			name:        "Missing_Variable",
+			body:        "Hello {{.name}}",
+			vars:        map[string]string{},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Invalid_Syntax",
+			body:        "Hello {{.name",
+			vars:        map[string]string{"name": "Ana"},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Range_Action",
+			body:        "{{range 1000000000}}x{{end}}",
+			vars:        map[string]string{},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Function_Call",
+			body:        `{{printf "%0999999999d" 1}}`,
+			vars:        map[string]string{},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Conditional",
+			body:        "{{if .name}}Hello {{.name}}{{end}}",
+			vars:        map[string]string{"name": "Ana"},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Variable_Declaration",
+			body:        "{{$x := .name}}",
+			vars:        map[string]string{"name": "Ana"},
+			expectedErr: service.ErrTemplateInvalid,
+		},
+		{
+			name:        "Rendered_Content_Too_Long",
+			body:        "{{.text}}",
+			vars:        map[string]string{"text": strings.Repeat("a", service.MaxContentLength+1)},
+			expectedErr: service.ErrContentTooLong,
+		},
+		{
+			name:        "Rendered_Content_Empty",
+			body:        "{{.text}}",
+			vars:        map[string]string{"text": ""},
+			expectedErr: service.ErrContentEmpty,
+		},
+		{
+			name:        "Rendered_Content_With_Control_Characters",
+			body:        "{{.text}}",
+			vars:        map[string]string{"text": "bell\a"},
+			expectedErr: service.ErrContentEncoding,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := service.RenderTemplate(tt.body, tt.vars)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Fatalf("Expected error %v, got: %v", tt.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, but got: %v", err)
+			}
+			if content != tt.expected {
+				t.Errorf("Expected content %q, got %q", tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestValidateContent_MaxLengthCountsCharacters(t *testing.T) {
+	content := strings.Repeat("ა", service.MaxContentLength)
+
+	if err := service.ValidateContent(content); err != nil {
+		t.Errorf("Expected %d multi-byte characters to be valid, got: %v", service.MaxContentLength, err)
+	}
+}
+
+func TestValidateNumber(t *testing.T) {
+	valid := []string{"+905551111111", "+995599123456"}
+	invalid := []string{"", "905551111111", "+0555111111", "+90 555 111 11 11", "+1234"}
+
+	for _, n := range valid {
+		if err := service.ValidateNumber(n); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", n, err)
+		}
+	}
+	for _, n := range invalid {
+		if err := service.ValidateNumber(n); err == nil {
+			t.Errorf("Expected %q to be invalid", n)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/LevanPro/insider/internal/repository"
+)
+
+// senderCacheTTL bounds how long a replica keeps using the webhook settings
+// of a tenant after another replica changed them.
+const senderCacheTTL = time.Minute
+
+// NewSenderFunc builds a sender for a webhook URL and auth key.
+type NewSenderFunc func(url, authKey string) Sender
+
+type cachedSender struct {
+	sender   Sender
+	provider string
+	loadedAt time.Time
+}
+
+// TenantSenders resolves the sender of a tenant. Messages without a tenant
+// use the default sender built from the application config, and so do
+// tenants without a provider of their own for pricing.
+type TenantSenders struct {
+	tenants         repository.TenantRepository
+	defaultSender   Sender
+	defaultProvider string
+	newSender       NewSenderFunc
+
+	mu    sync.Mutex
+	cache map[int64]cachedSender
+}
+
+func NewTenantSenders(tenants repository.TenantRepository, defaultSender Sender, defaultProvider string, newSender NewSenderFunc) *TenantSenders {
+	return &TenantSenders{
+		tenants:         tenants,
+		defaultSender:   defaultSender,
+		defaultProvider: defaultProvider,
+		newSender:       newSender,
+		cache:           make(map[int64]cachedSender),
+	}
+}
+
+func (r *TenantSenders) SenderFor(ctx context.Context, tenantID *int64) (Sender, string, error) {
+	if tenantID == nil {
+		return r.defaultSender, r.defaultProvider, nil
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[*tenantID]
+	r.mu.Unlock()
+
+	if ok && time.Since(cached.loadedAt) < senderCacheTTL {
+		return cached.sender, cached.provider, nil
+	}
+
+	tenant, err := r.tenants.Get(ctx, *tenantID)
+	if err != nil {
+		return nil, "", fmt.Errorf("load tenant %d: %w", *tenantID, err)
+	}
+
+	sender := r.newSender(tenant.WebhookURL, tenant.WebhookAuthKey)
+	provider := tenant.Provider
+	if provider == "" {
+		provider = r.defaultProvider
+	}
+
+	r.mu.Lock()
+	r.cache[*tenantID] = cachedSender{sender: sender, provider: provider, loadedAt: time.Now()}
+	r.mu.Unlock()
+
+	return sender, provider, nil
+}
+
+// Invalidate drops the cached sender of a tenant.
+func (r *TenantSenders) Invalidate(tenantID int64) {
+	r.mu.Lock()
+	delete(r.cache, tenantID)
+	r.mu.Unlock()
+}
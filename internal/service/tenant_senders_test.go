@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"github.com/LevanPro/insider/internal/service"
+)
+
+type fakeSender struct {
+	url string
+}
+
+func (s *fakeSender) Send(ctx context.Context, to, content string) (*service.SendResponse, error) {
+	return &service.SendResponse{}, nil
+}
+
+type fakeTenantRepo struct {
+	repository.TenantRepository
+	tenants map[int64]domain.Tenant
+	gets    int
+}
+
+func (r *fakeTenantRepo) Get(ctx context.Context, id int64) (domain.Tenant, error) {
+	r.gets++
+	t, ok := r.tenants[id]
+	if !ok {
+		return t, repository.ErrNotFound
+	}
+	return t, nil
+}
+
+func TestTenantSenders(t *testing.T) {
+	repo := &fakeTenantRepo{tenants: map[int64]domain.Tenant{
+		1: {ID: 1, WebhookURL: "https://tenant-1.example.com", WebhookAuthKey: "key-1", Provider: "acme"},
+		3: {ID: 3, WebhookURL: "https://tenant-3.example.com", WebhookAuthKey: "key-3"},
+	}}
+	defaultSender := &fakeSender{url: "https://default.example.com"}
+
+	senders := service.NewTenantSenders(repo, defaultSender, "default", func(url, authKey string) service.Sender {
+		return &fakeSender{url: url}
+	})
+
+	ctx := context.Background()
+
+	got, provider, err := senders.SenderFor(ctx, nil)
+	if err != nil || got != service.Sender(defaultSender) || provider != "default" {
+		t.Fatalf("SenderFor(nil) = %v, %q, %v, expected the default sender", got, provider, err)
+	}
+
+	tenantID := int64(1)
+	for range 2 {
+		got, provider, err = senders.SenderFor(ctx, &tenantID)
+		if err != nil {
+			t.Fatalf("SenderFor(1) error = %v", err)
+		}
+		if url := got.(*fakeSender).url; url != "https://tenant-1.example.com" || provider != "acme" {
+			t.Errorf("SenderFor(1) url = %q, provider = %q", url, provider)
+		}
+	}
+	if repo.gets != 1 {
+		t.Errorf("tenant loaded %d times, expected it to be cached", repo.gets)
+	}
+
+	senders.Invalidate(tenantID)
+	if _, _, err := senders.SenderFor(ctx, &tenantID); err != nil || repo.gets != 2 {
+		t.Errorf("SenderFor after Invalidate: err = %v, loads = %d", err, repo.gets)
+	}
+
+	withoutProvider := int64(3)
+	if _, provider, _ := senders.SenderFor(ctx, &withoutProvider); provider != "default" {
+		t.Errorf("SenderFor(3) provider = %q, expected the default provider", provider)
+	}
+
+	unknown := int64(2)
+	if _, _, err := senders.SenderFor(ctx, &unknown); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("SenderFor(2) error = %v, expected ErrNotFound", err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+	ErrInvalidTenant  = errors.New("invalid tenant")
+	// ErrUnknownTenant is returned when a new record refers to a tenant
+	// that does not exist.
+	ErrUnknownTenant = errors.New("tenant does not exist")
+)
+
+type TenantService struct {
+	repo    repository.TenantRepository
+	senders *TenantSenders
+	log     *zap.SugaredLogger
+}
+
+func NewTenantService(repo repository.TenantRepository, senders *TenantSenders, log *zap.SugaredLogger) *TenantService {
+	return &TenantService{
+		repo:    repo,
+		senders: senders,
+		log:     log,
+	}
+}
+
+func (s *TenantService) Create(ctx context.Context, t domain.Tenant) (domain.Tenant, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return domain.Tenant{}, fmt.Errorf("%w: name is required", ErrInvalidTenant)
+	}
+	if err := validateTenant(t); err != nil {
+		return domain.Tenant{}, err
+	}
+
+	created, err := s.repo.Create(ctx, t)
+	if errors.Is(err, repository.ErrConflict) {
+		return created, ErrTenantExists
+	}
+	if err != nil {
+		return created, err
+	}
+
+	s.log.Infow("Tenant created", "tenantID", created.ID, "name", created.Name)
+
+	return created, nil
+}
+
+func (s *TenantService) Get(ctx context.Context, id int64) (domain.Tenant, error) {
+	t, err := s.repo.Get(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return t, ErrTenantNotFound
+	}
+	return t, err
+}
+
+func (s *TenantService) List(ctx context.Context, limit, offset int) ([]domain.Tenant, error) {
+	return s.repo.List(ctx, limit, offset)
+}
+
+// Update replaces the webhook settings and quotas of a tenant. Workers pick
+// up the new settings with the next message of the tenant.
+func (s *TenantService) Update(ctx context.Context, t domain.Tenant) (domain.Tenant, error) {
+	if err := validateTenant(t); err != nil {
+		return domain.Tenant{}, err
+	}
+
+	updated, err := s.repo.Update(ctx, t)
+	if errors.Is(err, repository.ErrNotFound) {
+		return updated, ErrTenantNotFound
+	}
+	if err != nil {
+		return updated, err
+	}
+
+	s.senders.Invalidate(updated.ID)
+	s.log.Infow("Tenant updated", "tenantID", updated.ID, "dailyQuota", updated.Daily, "monthlyQuota", updated.Monthly)
+
+	return updated, nil
+}
+
+func validateTenant(t domain.Tenant) error {
+	u, err := url.Parse(t.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhook_url must be an absolute http(s) URL", ErrInvalidTenant)
+	}
+	if t.WebhookAuthKey == "" {
+		return fmt.Errorf("%w: webhook_auth_key is required", ErrInvalidTenant)
+	}
+	if err := ValidateQuota(t.Quota); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTenant, err)
+	}
+	return nil
+}
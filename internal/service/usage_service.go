@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LevanPro/insider/internal/domain"
+	"github.com/LevanPro/insider/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQuotaExceeded      = errors.New("quota exceeded")
+	ErrInvalidQuota       = errors.New("quotas must not be negative")
+	ErrInvalidUsagePeriod = errors.New("period must be day or month")
+)
+
+type UsageService struct {
+	repo repository.UsageRepository
+	log  *zap.SugaredLogger
+}
+
+func NewUsageService(repo repository.UsageRepository, log *zap.SugaredLogger) *UsageService {
+	return &UsageService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Charge records the usage of a message against the quotas of its tenant and
+// API key. When a quota is exceeded nothing is recorded and the quotas are
+// returned with ErrQuotaExceeded.
+func (s *UsageService) Charge(ctx context.Context, charge domain.UsageCharge) ([]domain.QuotaUsage, error) {
+	usages, err := s.repo.Consume(ctx, charge)
+	switch {
+	case errors.Is(err, repository.ErrQuotaExceeded):
+		return usages, quotaExceeded(usages)
+	case errors.Is(err, repository.ErrInvalidReference):
+		return nil, ErrUnknownTenant
+	case err != nil:
+		return nil, fmt.Errorf("charge usage: %w", err)
+	}
+	return usages, nil
+}
+
+// Release reverts a charge, e.g. when the message could not be stored.
+func (s *UsageService) Release(ctx context.Context, charge domain.UsageCharge) {
+	if err := s.repo.Release(ctx, charge); err != nil {
+		s.log.Errorw("Unable to release usage", "tenantID", charge.TenantID, "apiKeyID", charge.APIKeyID, "error", err)
+	}
+}
+
+// List reports consumption per period, tenant and API key.
+func (s *UsageService) List(ctx context.Context, filter domain.UsageFilter) ([]domain.UsageRecord, error) {
+	if filter.Period == "" {
+		filter.Period = domain.UsagePeriodDay
+	}
+	if filter.Period != domain.UsagePeriodDay && filter.Period != domain.UsagePeriodMonth {
+		return nil, ErrInvalidUsagePeriod
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// ValidateQuota checks the limits of a tenant or API key.
+func ValidateQuota(q domain.Quota) error {
+	if (q.Daily != nil && *q.Daily < 0) || (q.Monthly != nil && *q.Monthly < 0) {
+		return ErrInvalidQuota
+	}
+	return nil
+}
+
+func quotaExceeded(usages []domain.QuotaUsage) error {
+	var exceeded []string
+	for _, u := range usages {
+		if u.Exceeded {
+			exceeded = append(exceeded, fmt.Sprintf("%s %d %s quota of %d segments", u.Subject, u.SubjectID, u.Period, u.Limit))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrQuotaExceeded, strings.Join(exceeded, ", "))
+}
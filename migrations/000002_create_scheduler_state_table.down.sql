@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scheduler_state;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE scheduler_state (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    running BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO scheduler_state (id, running) VALUES (1, TRUE);
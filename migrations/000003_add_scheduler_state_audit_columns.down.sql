@@ -0,0 +1,3 @@
+ALTER TABLE scheduler_state
+    DROP COLUMN IF EXISTS updated_by,
+    DROP COLUMN IF EXISTS reason;
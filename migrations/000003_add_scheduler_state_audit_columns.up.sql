@@ -0,0 +1,3 @@
+ALTER TABLE scheduler_state
+    ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT 'system',
+    ADD COLUMN reason TEXT NULL;
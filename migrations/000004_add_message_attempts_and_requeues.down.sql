@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS message_requeues;
+
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS last_error;
@@ -0,0 +1,16 @@
+ALTER TABLE messages
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT NULL;
+
+-- message_id has no foreign key so the requeue history outlives purged messages.
+CREATE TABLE message_requeues (
+    id BIGSERIAL PRIMARY KEY,
+    message_id BIGINT NOT NULL,
+    previous_attempts INT NOT NULL,
+    previous_error TEXT NULL,
+    requeued_by VARCHAR(255) NOT NULL,
+    reason TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_message_requeues_message_id ON message_requeues(message_id);
@@ -0,0 +1,3 @@
+-- Postgres cannot drop enum values; move affected rows back to known states.
+UPDATE messages SET status = 'pending' WHERE status::text = 'processing';
+UPDATE messages SET status = 'failed', last_error = 'cancelled' WHERE status::text = 'cancelled';
@@ -0,0 +1,2 @@
+ALTER TYPE message_status ADD VALUE IF NOT EXISTS 'processing';
+ALTER TYPE message_status ADD VALUE IF NOT EXISTS 'cancelled';
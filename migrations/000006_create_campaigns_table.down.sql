@@ -0,0 +1,5 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS campaign_id;
+
+DROP TABLE IF EXISTS campaigns;
+
+DROP TYPE IF EXISTS campaign_status;
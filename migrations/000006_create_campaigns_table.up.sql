@@ -0,0 +1,15 @@
+ALTER TYPE message_status ADD VALUE IF NOT EXISTS 'delivered';
+
+CREATE TYPE campaign_status AS ENUM ('active', 'paused');
+
+CREATE TABLE campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    status campaign_status NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE messages ADD COLUMN campaign_id BIGINT NULL REFERENCES campaigns(id);
+
+CREATE INDEX idx_messages_campaign_id ON messages(campaign_id);
@@ -0,0 +1,3 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS templates;
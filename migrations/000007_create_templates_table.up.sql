@@ -0,0 +1,11 @@
+CREATE TABLE templates (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    locale VARCHAR(16) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (name, locale)
+);
+
+ALTER TABLE messages ADD COLUMN template_id BIGINT NULL REFERENCES templates(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS suppressions;
+
+UPDATE messages SET status = 'cancelled', last_error = 'recipient suppressed' WHERE status::text = 'suppressed';
@@ -0,0 +1,9 @@
+ALTER TYPE message_status ADD VALUE IF NOT EXISTS 'suppressed';
+
+CREATE TABLE suppressions (
+    number VARCHAR(20) PRIMARY KEY,
+    reason TEXT NOT NULL DEFAULT '',
+    source VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_to;
+
+DROP TABLE IF EXISTS inbound_messages;
@@ -0,0 +1,14 @@
+CREATE TABLE inbound_messages (
+    id BIGSERIAL PRIMARY KEY,
+    "from" VARCHAR(20) NOT NULL,
+    "to" VARCHAR(20) NULL,
+    content TEXT NOT NULL,
+    external_id VARCHAR(100) NULL UNIQUE,
+    message_id BIGINT NULL REFERENCES messages(id) ON DELETE SET NULL,
+    opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_inbound_messages_from ON inbound_messages("from");
+CREATE INDEX idx_messages_to ON messages("to");
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_to_sent_at;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS not_before;
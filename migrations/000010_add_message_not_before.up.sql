@@ -0,0 +1,3 @@
+ALTER TABLE messages ADD COLUMN not_before TIMESTAMPTZ NULL;
+
+CREATE INDEX idx_messages_to_sent_at ON messages("to", sent_at) WHERE sent_at IS NOT NULL;
@@ -0,0 +1,10 @@
+ALTER TABLE campaigns DROP COLUMN IF EXISTS dedup_window_seconds;
+
+DROP INDEX IF EXISTS idx_messages_to_content_hash;
+
+UPDATE messages SET status = 'cancelled' WHERE status::text = 'duplicate';
+
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS dedup_window_seconds,
+    DROP COLUMN IF EXISTS duplicate_of;
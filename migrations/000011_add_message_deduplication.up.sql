@@ -0,0 +1,12 @@
+ALTER TYPE message_status ADD VALUE IF NOT EXISTS 'duplicate';
+
+ALTER TABLE messages
+    ADD COLUMN content_hash CHAR(64) NULL,
+    ADD COLUMN dedup_window_seconds INT NULL,
+    ADD COLUMN duplicate_of BIGINT NULL;
+
+UPDATE messages SET content_hash = encode(sha256(convert_to(content, 'UTF8')), 'hex');
+
+CREATE INDEX idx_messages_to_content_hash ON messages("to", content_hash);
+
+ALTER TABLE campaigns ADD COLUMN dedup_window_seconds INT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target VARCHAR(255) NOT NULL DEFAULT '',
+    params JSONB NULL,
+    source_ip INET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, created_at);
+CREATE INDEX idx_audit_log_action ON audit_log(action, created_at);
\ No newline at end of file
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_campaigns_tenant_id;
+DROP INDEX IF EXISTS idx_messages_tenant_pending;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE messages DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE tenants (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    webhook_url TEXT NOT NULL,
+    webhook_auth_key TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A NULL tenant_id belongs to the default tenant, which sends through the
+-- application webhook.
+ALTER TABLE messages ADD COLUMN tenant_id BIGINT NULL REFERENCES tenants(id);
+ALTER TABLE campaigns ADD COLUMN tenant_id BIGINT NULL REFERENCES tenants(id);
+ALTER TABLE api_keys ADD COLUMN tenant_id BIGINT NULL REFERENCES tenants(id);
+
+CREATE INDEX idx_messages_tenant_pending ON messages(tenant_id, id) WHERE status = 'pending';
+CREATE INDEX idx_campaigns_tenant_id ON campaigns(tenant_id);
\ No newline at end of file
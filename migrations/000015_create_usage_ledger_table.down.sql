@@ -0,0 +1,9 @@
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS daily_quota,
+    DROP COLUMN IF EXISTS monthly_quota;
+
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS daily_quota,
+    DROP COLUMN IF EXISTS monthly_quota;
+
+DROP TABLE IF EXISTS usage_ledger;
\ No newline at end of file
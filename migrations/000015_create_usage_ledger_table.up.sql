@@ -0,0 +1,22 @@
+CREATE TABLE usage_ledger (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id BIGINT NULL REFERENCES tenants(id),
+    api_key_id BIGINT NULL REFERENCES api_keys(id),
+    day DATE NOT NULL,
+    messages INT NOT NULL DEFAULT 0,
+    segments INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_usage_ledger_subject_day
+    ON usage_ledger ((COALESCE(tenant_id, 0)), (COALESCE(api_key_id, 0)), day);
+CREATE INDEX idx_usage_ledger_day ON usage_ledger(day);
+
+-- Quotas are in segments, i.e. billed SMS. NULL means unlimited.
+ALTER TABLE tenants
+    ADD COLUMN daily_quota INT NULL,
+    ADD COLUMN monthly_quota INT NULL;
+
+ALTER TABLE api_keys
+    ADD COLUMN daily_quota INT NULL,
+    ADD COLUMN monthly_quota INT NULL;
\ No newline at end of file
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_messages_sent_at;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS provider;
+
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS provider,
+    DROP COLUMN IF EXISTS segments,
+    DROP COLUMN IF EXISTS unit_price;
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Cost of a sent message: the provider it went through, its segment count
+-- and the price per segment when it was sent. unit_price is NULL when the
+-- price table has no entry for the recipient.
+ALTER TABLE messages
+    ADD COLUMN provider VARCHAR(64) NULL,
+    ADD COLUMN segments INT NULL,
+    ADD COLUMN unit_price NUMERIC(12, 6) NULL;
+
+-- An empty provider sends through the application provider.
+ALTER TABLE tenants ADD COLUMN provider VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_messages_sent_at ON messages(sent_at) WHERE sent_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,32 @@
+CREATE TABLE imports (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    filename TEXT NOT NULL DEFAULT '',
+    columns TEXT[] NOT NULL,
+    template_id BIGINT NULL REFERENCES templates(id) ON DELETE SET NULL,
+    campaign_id BIGINT NULL REFERENCES campaigns(id),
+    tenant_id BIGINT NULL REFERENCES tenants(id),
+    created_by VARCHAR(255) NOT NULL,
+    total_rows INT NOT NULL,
+    processed_rows INT NOT NULL DEFAULT 0,
+    accepted_rows INT NOT NULL DEFAULT 0,
+    rejected_rows INT NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX idx_imports_tenant_id ON imports(tenant_id);
+
+-- Rejected rows keep their original values, so the error report can be
+-- fixed and uploaded again.
+CREATE TABLE import_errors (
+    id BIGSERIAL PRIMARY KEY,
+    import_id BIGINT NOT NULL REFERENCES imports(id) ON DELETE CASCADE,
+    line INT NOT NULL,
+    record TEXT[] NOT NULL,
+    reason TEXT NOT NULL
+);
+
+CREATE INDEX idx_import_errors_import_id ON import_errors(import_id, line);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS retention_runs;
+DROP INDEX IF EXISTS idx_messages_finished_at;
+DROP TABLE IF EXISTS messages_archive;
\ No newline at end of file
@@ -0,0 +1,27 @@
+-- Archived messages keep the columns of messages; columns added to messages
+-- later must be added here too.
+CREATE TABLE messages_archive (LIKE messages);
+
+ALTER TABLE messages_archive
+    ADD PRIMARY KEY (id),
+    ADD COLUMN archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX idx_messages_archive_to ON messages_archive("to");
+CREATE INDEX idx_messages_archive_created_at ON messages_archive(created_at);
+
+-- Retention picks messages by the time they finished.
+CREATE INDEX idx_messages_finished_at ON messages((COALESCE(sent_at, updated_at)));
+
+CREATE TABLE retention_runs (
+    id BIGSERIAL PRIMARY KEY,
+    mode VARCHAR(20) NOT NULL,
+    cutoff TIMESTAMPTZ NOT NULL,
+    purged INT NOT NULL,
+    by_status JSONB NOT NULL,
+    batches INT NOT NULL,
+    error TEXT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_retention_runs_started_at ON retention_runs(started_at);
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE messages_archive
+    DROP COLUMN IF EXISTS key_id,
+    DROP COLUMN IF EXISTS dek,
+    ALTER COLUMN content TYPE VARCHAR(160);
+
+ALTER TABLE messages
+    DROP COLUMN IF EXISTS key_id,
+    DROP COLUMN IF EXISTS dek,
+    ALTER COLUMN content TYPE VARCHAR(160);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Envelope encryption of content. Rows with a NULL key_id hold plaintext
+-- content; otherwise content is base64 ciphertext and dek the data key,
+-- wrapped by the key encryption key key_id. Sealed content is longer than the
+-- 160 characters it holds, so content is no longer limited by the column.
+ALTER TABLE messages
+    ALTER COLUMN content TYPE TEXT,
+    ADD COLUMN key_id VARCHAR(64) NULL,
+    ADD COLUMN dek BYTEA NULL;
+
+ALTER TABLE messages_archive
+    ALTER COLUMN content TYPE TEXT,
+    ADD COLUMN key_id VARCHAR(64) NULL,
+    ADD COLUMN dek BYTEA NULL;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_templates_tenant_name_locale;
+
+ALTER TABLE templates
+    DROP COLUMN IF EXISTS tenant_id,
+    ADD CONSTRAINT templates_name_locale_key UNIQUE (name, locale);
\ No newline at end of file
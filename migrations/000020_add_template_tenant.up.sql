@@ -0,0 +1,7 @@
+-- Templates belong to a tenant like campaigns; a NULL tenant_id belongs to
+-- the default tenant. Names are unique per tenant.
+ALTER TABLE templates
+    ADD COLUMN tenant_id BIGINT NULL REFERENCES tenants(id),
+    DROP CONSTRAINT IF EXISTS templates_name_locale_key;
+
+CREATE UNIQUE INDEX idx_templates_tenant_name_locale ON templates((COALESCE(tenant_id, 0)), name, locale);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS cap_reserved_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Messages reserve their place under the frequency cap of their recipient
+-- before they are sent, so concurrent workers cannot exceed it.
+ALTER TABLE messages ADD COLUMN cap_reserved_at TIMESTAMPTZ NULL;
\ No newline at end of file